@@ -0,0 +1,115 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package basex 提供二进制到文本的编码：base62、base58（比特币字母表）、
+// Crockford base32（可带校验符）和 Z85，以及编码注册表和流式编码器。
+package basex
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Encoding 是二进制到文本的编码
+type Encoding interface {
+	EncodeToString(src []byte) string
+	DecodeString(s string) ([]byte, error)
+}
+
+var (
+	// 输入包含不属于字母表的字符
+	ErrInvalidChar = errors.New("basex: invalid character")
+	// 未注册的编码名称
+	ErrUnknownEncoding = errors.New("basex: unknown encoding")
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Encoding{}
+)
+
+// Register 以 name 注册编码，已存在时覆盖
+func Register(name string, enc Encoding) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = enc
+}
+
+// Get 返回已注册的编码
+func Get(name string) (Encoding, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	enc, ok := registry[name]
+	if !ok {
+		return nil, ErrUnknownEncoding
+	}
+	return enc, nil
+}
+
+// errEncoder 是编码可能失败的编码，如输入长度有要求的 Z85
+type errEncoder interface {
+	Encode(src []byte) ([]byte, error)
+}
+
+// Encode 使用 enc 编码 src，enc 提供 Encode([]byte) ([]byte, error) 方法时返回其错误，
+// 而不是像 EncodeToString 那样返回空字符串
+func Encode(enc Encoding, src []byte) (string, error) {
+	if e, ok := enc.(errEncoder); ok {
+		dst, err := e.Encode(src)
+		if err != nil {
+			return "", err
+		}
+		return string(dst), nil
+	}
+	return enc.EncodeToString(src), nil
+}
+
+// Names 返回所有已注册的编码名称（已排序）
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type hexEncoding struct{}
+
+func (hexEncoding) EncodeToString(src []byte) string { return hex.EncodeToString(src) }
+
+func (hexEncoding) DecodeString(s string) ([]byte, error) { return hex.DecodeString(s) }
+
+// Hex 十六进制编码
+var Hex Encoding = hexEncoding{}
+
+func init() {
+	Register("hex", Hex)
+	Register("base32", base32.StdEncoding)
+	Register("base64", base64.StdEncoding)
+	Register("base64url", base64.RawURLEncoding)
+	Register("base58", Base58)
+	Register("base62", Base62)
+	Register("crockford", Crockford)
+	Register("crockford-check", CrockfordCheck)
+	Register("z85", Z85)
+}
@@ -0,0 +1,140 @@
+package basex
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestRadix(t *testing.T) {
+	if Base58.EncodeToString([]byte("Hello World!")) != "2NEpo7TZRRrLZSi2U" {
+		t.Fatal("base58 encode error")
+	}
+	if Base58.EncodeToString([]byte{0, 0, 1}) != "112" {
+		t.Fatal("base58 leading zeros error")
+	}
+	for i := 0; i < 50; i++ {
+		b := make([]byte, i)
+		rand.Read(b)
+		if i > 2 {
+			b[0] = 0
+		}
+		for _, enc := range []*RadixEncoding{Base58, Base62} {
+			out, err := enc.DecodeString(enc.EncodeToString(b))
+			if err != nil || !bytes.Equal(out, b) {
+				t.Fatalf("radix round trip error: %x", b)
+			}
+		}
+		if i > 0 && b[0] != 0 {
+			if Base62.EncodeToString(b) != new(big.Int).SetBytes(b).Text(62) {
+				t.Fatal("base62 should match math/big")
+			}
+		}
+	}
+	if _, err := Base58.DecodeString("0OIl"); err != ErrInvalidChar {
+		t.Fatal("base58 invalid char")
+	}
+}
+
+func TestCrockford(t *testing.T) {
+	// 校验符按编码结果表示的数值（含补齐的0位）计算，如 "04" 为4，"ZW" 为1020
+	checks := map[string]string{"\x01": "044", "\xff": "ZWN", "\x08": "10*", "": "0"}
+	for in, want := range checks {
+		if got := CrockfordCheck.EncodeToString([]byte(in)); got != want {
+			t.Fatalf("crockford check encode %q = %q, want %q", in, got, want)
+		}
+		if out, err := CrockfordCheck.DecodeString(want); err != nil || string(out) != in {
+			t.Fatalf("crockford check decode %q error", want)
+		}
+	}
+	if Crockford.EncodeToString([]byte{1}) != "04" {
+		t.Fatal("crockford encode error")
+	}
+	b := []byte("crockford base32")
+	s := CrockfordCheck.EncodeToString(b)
+	out, err := CrockfordCheck.DecodeString(s)
+	if err != nil || !bytes.Equal(out, b) {
+		t.Fatal("crockford check round trip error")
+	}
+	if out, err := Crockford.DecodeString("o4-"); err != nil || !bytes.Equal(out, []byte{1}) {
+		t.Fatal("crockford normalize error")
+	}
+	bad := []byte(s)
+	bad[len(bad)-1] = '~'
+	if bad[len(bad)-1] == s[len(s)-1] {
+		bad[len(bad)-1] = '*'
+	}
+	if _, err = CrockfordCheck.DecodeString(string(bad)); err != ErrInvalidChecksum {
+		t.Fatal("crockford checksum error")
+	}
+}
+
+func TestZ85(t *testing.T) {
+	raw := []byte{0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B}
+	if Z85.EncodeToString(raw) != "HelloWorld" {
+		t.Fatal("z85 encode error")
+	}
+	out, err := Z85.DecodeString("HelloWorld")
+	if err != nil || !bytes.Equal(out, raw) {
+		t.Fatal("z85 decode error")
+	}
+	if _, err = Z85.DecodeString("Hello"[:4]); err != ErrZ85Length {
+		t.Fatal("z85 length error")
+	}
+	if Z85.EncodeToString([]byte{1}) != "" {
+		t.Fatal("z85 invalid length should be empty")
+	}
+	if _, err = Encode(Z85, []byte{1}); err != ErrZ85Length {
+		t.Fatal("z85 Encode should return length error")
+	}
+	if s, err := Encode(Hex, []byte{1}); err != nil || s != "01" {
+		t.Fatal("Encode hex error")
+	}
+}
+
+func TestRegistryAndStream(t *testing.T) {
+	data := make([]byte, 1000)
+	rand.Read(data)
+	for _, name := range Names() {
+		enc, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		w := NewEncoder(enc, &buf)
+		for i := 0; i < len(data); i += 7 {
+			end := i + 7
+			if end > len(data) {
+				end = len(data)
+			}
+			w.Write(data[i:end])
+		}
+		if err = w.Close(); err != nil {
+			t.Fatalf("%s stream error: %v", name, err)
+		}
+		if buf.String() != enc.EncodeToString(data) {
+			t.Fatalf("%s stream result differs", name)
+		}
+	}
+	if _, err := Get("base91"); err != ErrUnknownEncoding {
+		t.Fatal("unknown encoding")
+	}
+	w := NewEncoder(Z85, &bytes.Buffer{})
+	w.Write([]byte{1, 2, 3})
+	if w.Close() != ErrZ85Length {
+		t.Fatal("z85 stream length error")
+	}
+	// 不可比较的自定义编码
+	var buf bytes.Buffer
+	w = NewEncoder(sliceEncoding{Hex, nil}, &buf)
+	w.Write([]byte{1, 2})
+	if w.Close() != nil || buf.String() != "0102" {
+		t.Fatal("uncomparable encoding stream error")
+	}
+}
+
+type sliceEncoding struct {
+	Encoding
+	extra []byte
+}
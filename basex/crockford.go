@@ -0,0 +1,86 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package basex
+
+import (
+	"encoding/base32"
+	"errors"
+	"strings"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// 校验符为编码结果（含补齐的0位）所表示的数值模37的结果，前32个与字母表相同
+	crockfordCheckSymbols = crockfordAlphabet + "*~$=U"
+)
+
+var crockfordBase32 = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+
+// crockfordNormalize 按 Crockford 规范处理输入：不区分大小写，I、L 视为1，O 视为0，忽略连字符
+var crockfordNormalize = strings.NewReplacer("-", "", "I", "1", "L", "1", "O", "0")
+
+// CrockfordEncoding 是 Crockford base32 编码
+type CrockfordEncoding struct {
+	check bool
+}
+
+var (
+	// Crockford 不带校验符的 Crockford base32
+	Crockford = &CrockfordEncoding{}
+	// CrockfordCheck 末尾带一个校验符的 Crockford base32
+	CrockfordCheck = &CrockfordEncoding{check: true}
+)
+
+// 校验符不匹配
+var ErrInvalidChecksum = errors.New("basex: invalid checksum")
+
+// crockfordMod37 计算编码结果 s 所表示的数值模37的值，r 为之前部分的结果，s 必须已规范化
+func crockfordMod37(r int, s string) int {
+	for i := 0; i < len(s); i++ {
+		r = (r*32 + strings.IndexByte(crockfordAlphabet, s[i])) % 37
+	}
+	return r
+}
+
+// EncodeToString 编码
+func (e *CrockfordEncoding) EncodeToString(src []byte) string {
+	s := crockfordBase32.EncodeToString(src)
+	if e.check {
+		s += string(crockfordCheckSymbols[crockfordMod37(0, s)])
+	}
+	return s
+}
+
+// DecodeString 解码，输入不区分大小写并忽略连字符
+func (e *CrockfordEncoding) DecodeString(s string) ([]byte, error) {
+	s = crockfordNormalize.Replace(strings.ToUpper(s))
+	var check byte
+	if e.check {
+		if s == "" {
+			return nil, ErrInvalidChecksum
+		}
+		s, check = s[:len(s)-1], s[len(s)-1]
+	}
+	out, err := crockfordBase32.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidChar
+	}
+	if e.check && crockfordCheckSymbols[crockfordMod37(0, s)] != check {
+		return nil, ErrInvalidChecksum
+	}
+	return out, nil
+}
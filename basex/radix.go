@@ -0,0 +1,114 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package basex
+
+import "strings"
+
+// RadixEncoding 将字节串视为大整数并按字母表长度进制表示的编码（如 base58、base62），
+// 开头的零字节编码为字母表的第一个字符以保持长度信息
+type RadixEncoding struct {
+	alphabet string
+	decode   [256]int
+}
+
+// NewRadixEncoding 使用 alphabet（ASCII、无重复字符、长度2-256）创建编码
+func NewRadixEncoding(alphabet string) *RadixEncoding {
+	if len(alphabet) < 2 {
+		panic("basex: alphabet too short")
+	}
+	e := &RadixEncoding{alphabet: alphabet}
+	for i := range e.decode {
+		e.decode[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		if e.decode[alphabet[i]] >= 0 {
+			panic("basex: duplicate character in alphabet")
+		}
+		e.decode[alphabet[i]] = i
+	}
+	return e
+}
+
+var (
+	// Base58 比特币字母表的 base58 编码
+	Base58 = NewRadixEncoding("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+	// Base62 使用 0-9a-zA-Z 字母表（与 math/big 一致）的 base62 编码
+	Base62 = NewRadixEncoding("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+)
+
+// EncodeToString 编码
+func (e *RadixEncoding) EncodeToString(src []byte) string {
+	base := len(e.alphabet)
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+	// 以 base 进制存储的各位，低位在前
+	digits := make([]int, 0, len(src)*2)
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += digits[i] << 8
+			digits[i] = carry % base
+			carry /= base
+		}
+		for carry > 0 {
+			digits = append(digits, carry%base)
+			carry /= base
+		}
+	}
+	var sb strings.Builder
+	sb.Grow(zeros + len(digits))
+	for i := 0; i < zeros; i++ {
+		sb.WriteByte(e.alphabet[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteByte(e.alphabet[digits[i]])
+	}
+	return sb.String()
+}
+
+// DecodeString 解码
+func (e *RadixEncoding) DecodeString(s string) ([]byte, error) {
+	base := len(e.alphabet)
+	zeros := 0
+	for zeros < len(s) && s[zeros] == e.alphabet[0] {
+		zeros++
+	}
+	// 以256进制存储的各字节，低位在前
+	out := make([]byte, 0, len(s))
+	for i := zeros; i < len(s); i++ {
+		carry := e.decode[s[i]]
+		if carry < 0 {
+			return nil, ErrInvalidChar
+		}
+		for j := range out {
+			carry += int(out[j]) * base
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			out = append(out, byte(carry))
+			carry >>= 8
+		}
+	}
+	res := make([]byte, zeros+len(out))
+	for i, b := range out {
+		res[len(res)-1-i] = b
+	}
+	return res, nil
+}
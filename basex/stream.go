@@ -0,0 +1,101 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package basex
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"io"
+)
+
+// blockSize 返回按块编码的编码每块的输入字节数，块可以独立编码，因此无需缓存全部数据；
+// 其他编码返回0。使用 switch 而非以 Encoding 为键的 map，自定义编码的类型不可比较时也不会 panic
+func blockSize(enc Encoding) int {
+	switch enc {
+	case Z85:
+		return 4
+	case Crockford, base32.StdEncoding:
+		return 5
+	case Hex:
+		return 1
+	case base64.StdEncoding, base64.RawURLEncoding:
+		return 3
+	}
+	return 0
+}
+
+type encoder struct {
+	enc   Encoding
+	w     io.Writer
+	block int
+	buf   bytes.Buffer
+	// Crockford 校验符需要对全部数据计算，流式累积
+	mod37 int
+	check bool
+	err   error
+}
+
+// NewEncoder 返回流式编码器：写入的数据编码后写入 w，写入完毕必须调用 Close。
+// Z85、Crockford、hex 按块编码，内存占用固定；base58、base62 等进制编码需要整体运算，
+// 会缓存全部数据直到 Close
+func NewEncoder(enc Encoding, w io.Writer) io.WriteCloser {
+	e := &encoder{enc: enc, w: w, block: blockSize(enc)}
+	if enc == CrockfordCheck {
+		e.enc, e.block, e.check = Crockford, 5, true
+	}
+	return e
+}
+
+func (e *encoder) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	e.buf.Write(p)
+	if e.block > 0 && e.buf.Len() >= e.block {
+		n := e.buf.Len() / e.block * e.block
+		e.emit(e.enc.EncodeToString(e.buf.Next(n)))
+	}
+	return len(p), e.err
+}
+
+// emit 写入编码结果，按块编码的结果依次拼接即为整体的编码结果，因此可以流式累积校验值
+func (e *encoder) emit(s string) {
+	if e.check {
+		e.mod37 = crockfordMod37(e.mod37, s)
+	}
+	_, e.err = io.WriteString(e.w, s)
+}
+
+// Close 编码剩余数据并写入，不会关闭底层的 Writer
+func (e *encoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.enc == Z85 && e.buf.Len() > 0 {
+		return ErrZ85Length
+	}
+	if e.buf.Len() > 0 || e.block == 0 {
+		if e.emit(e.enc.EncodeToString(e.buf.Bytes())); e.err != nil {
+			return e.err
+		}
+	}
+	if e.check {
+		_, e.err = io.WriteString(e.w, string(crockfordCheckSymbols[e.mod37]))
+	}
+	return e.err
+}
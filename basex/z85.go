@@ -0,0 +1,88 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package basex
+
+import "errors"
+
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// 输入长度不符合 Z85 规范
+var ErrZ85Length = errors.New("basex: z85 input length must be a multiple of 4 (decoded) or 5 (encoded)")
+
+var z85Decode [256]int
+
+func init() {
+	for i := range z85Decode {
+		z85Decode[i] = -1
+	}
+	for i := 0; i < len(z85Alphabet); i++ {
+		z85Decode[z85Alphabet[i]] = i
+	}
+}
+
+type z85Encoding struct{}
+
+// Z85 是 ZeroMQ 定义的 Z85 编码（RFC 32/Z85），每4字节编码为5个字符，输入长度必须是4的倍数
+var Z85 Encoding = z85Encoding{}
+
+// Encode 编码，len(src) 必须是4的倍数
+func (z85Encoding) Encode(src []byte) ([]byte, error) {
+	if len(src)%4 != 0 {
+		return nil, ErrZ85Length
+	}
+	dst := make([]byte, len(src)/4*5)
+	for i, j := 0, 0; i < len(src); i, j = i+4, j+5 {
+		v := uint32(src[i])<<24 | uint32(src[i+1])<<16 | uint32(src[i+2])<<8 | uint32(src[i+3])
+		for k := 4; k >= 0; k-- {
+			dst[j+k] = z85Alphabet[v%85]
+			v /= 85
+		}
+	}
+	return dst, nil
+}
+
+// EncodeToString 编码，长度不是4的倍数时返回空字符串，需要错误信息时请使用 Encode
+func (e z85Encoding) EncodeToString(src []byte) string {
+	dst, err := e.Encode(src)
+	if err != nil {
+		return ""
+	}
+	return string(dst)
+}
+
+// DecodeString 解码，len(s) 必须是5的倍数
+func (z85Encoding) DecodeString(s string) ([]byte, error) {
+	if len(s)%5 != 0 {
+		return nil, ErrZ85Length
+	}
+	dst := make([]byte, len(s)/5*4)
+	for i, j := 0, 0; i < len(s); i, j = i+5, j+4 {
+		var v uint64
+		for k := 0; k < 5; k++ {
+			d := z85Decode[s[i+k]]
+			if d < 0 {
+				return nil, ErrInvalidChar
+			}
+			v = v*85 + uint64(d)
+		}
+		if v > 0xFFFFFFFF {
+			return nil, ErrInvalidChar
+		}
+		dst[j], dst[j+1], dst[j+2], dst[j+3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+	}
+	return dst, nil
+}
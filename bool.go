@@ -0,0 +1,70 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"strings"
+	"sync"
+)
+
+// BoolParser 可配置的布尔值解析器，忽略大小写和首尾空白，词表可通过 Register 扩展。
+// 零值是词表为空的可用解析器
+type BoolParser struct {
+	mu    sync.RWMutex
+	words map[string]bool
+}
+
+// NewBoolParser 创建一个带有默认词表的解析器，默认词表：
+//
+// true: 1、t、true、on、y、yes、enable、enabled、是、开
+//
+// false: 0、f、false、off、n、no、disable、disabled、否、关
+func NewBoolParser() *BoolParser {
+	p := &BoolParser{words: make(map[string]bool)}
+	p.Register(true, "1", "t", "true", "on", "y", "yes", "enable", "enabled", "是", "开")
+	p.Register(false, "0", "f", "false", "off", "n", "no", "disable", "disabled", "否", "关")
+	return p
+}
+
+// Register 注册词表，words 被解析为 value，已存在的词会被覆盖
+func (p *BoolParser) Register(value bool, words ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.words == nil {
+		p.words = make(map[string]bool)
+	}
+	for _, w := range words {
+		p.words[strings.ToLower(strings.TrimSpace(w))] = value
+	}
+}
+
+// Parse 解析布尔值，ok 为false表示 v 不在词表中（此时 value 也为false）
+func (p *BoolParser) Parse(v string) (value bool, ok bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	value, ok = p.words[strings.ToLower(strings.TrimSpace(v))]
+	return
+}
+
+// DefaultBoolParser 默认解析器，IsTrue、IsFalse 均基于此实现，
+// 可调用其 Register 方法全局扩展词表
+var DefaultBoolParser = NewBoolParser()
+
+// ParseBool 使用 DefaultBoolParser 解析布尔值，ok 为false表示无法识别
+func ParseBool(v string) (value bool, ok bool) {
+	return DefaultBoolParser.Parse(v)
+}
@@ -0,0 +1,47 @@
+package gtc
+
+import "testing"
+
+func TestBoolParser(t *testing.T) {
+	for _, v := range []string{"yes", " Y ", "Enabled", "是", "开", "tRuE", "ON"} {
+		if b, ok := ParseBool(v); !b || !ok {
+			t.Fatalf("%q should be true", v)
+		}
+		if !IsTrue(v) || IsFalse(v) {
+			t.Fatalf("%q IsTrue/IsFalse error", v)
+		}
+	}
+	for _, v := range []string{"no", "N", "disable\t", "否", "关", "Off"} {
+		if b, ok := ParseBool(v); b || !ok {
+			t.Fatalf("%q should be false", v)
+		}
+		if IsTrue(v) || !IsFalse(v) {
+			t.Fatalf("%q IsTrue/IsFalse error", v)
+		}
+	}
+	if b, ok := ParseBool("maybe"); b || ok {
+		t.Fatal("unknown value should not be ok")
+	}
+
+	p := NewBoolParser()
+	p.Register(true, "ok", "对")
+	p.Register(false, "yes")
+	if b, ok := p.Parse(" OK "); !b || !ok {
+		t.Fatal("registered word should be true")
+	}
+	if b, ok := p.Parse("yes"); b || !ok {
+		t.Fatal("overwritten word should be false")
+	}
+	if _, ok := ParseBool("对"); ok {
+		t.Fatal("custom parser should not affect default parser")
+	}
+
+	var zero BoolParser
+	if _, ok := zero.Parse("yes"); ok {
+		t.Fatal("zero parser should have empty words")
+	}
+	zero.Register(true, "ok")
+	if b, ok := zero.Parse("ok"); !b || !ok {
+		t.Fatal("zero parser Register error")
+	}
+}
@@ -0,0 +1,186 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SplitWords 将标识符拆分为单词，识别下划线、连字符、空格等分隔符和大小写边界，
+// 连续的大写字母视为缩写词，如 "HTTPServer" 拆分为 ["HTTP", "Server"]
+func SplitWords(s string) []string {
+	var words []string
+	rs := []rune(s)
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		prev := rs[i-1]
+		boundary := false
+		switch {
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			// fooBar、v2Api
+			boundary = true
+		case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1]):
+			// HTTPServer 中的 S
+			boundary = true
+		}
+		if boundary {
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+func joinWords(s, sep string, conv func(i int, w string) string) string {
+	words := SplitWords(s)
+	for i, w := range words {
+		words[i] = conv(i, w)
+	}
+	return strings.Join(words, sep)
+}
+
+func lowerWord(_ int, w string) string {
+	return strings.ToLower(w)
+}
+
+func upperWord(_ int, w string) string {
+	return strings.ToUpper(w)
+}
+
+// titleWord 首字母大写，其余小写
+func titleWord(_ int, w string) string {
+	rs := []rune(strings.ToLower(w))
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
+
+// ToSnakeCase 转换为 snake_case，如 HTTPServer -> http_server
+func ToSnakeCase(s string) string {
+	return joinWords(s, "_", lowerWord)
+}
+
+// ToKebabCase 转换为 kebab-case，如 HTTPServer -> http-server
+func ToKebabCase(s string) string {
+	return joinWords(s, "-", lowerWord)
+}
+
+// ToScreamingCase 转换为 SCREAMING_SNAKE_CASE，如 HTTPServer -> HTTP_SERVER
+func ToScreamingCase(s string) string {
+	return joinWords(s, "_", upperWord)
+}
+
+// ToPascalCase 转换为 PascalCase，如 http_server -> HttpServer
+func ToPascalCase(s string) string {
+	return joinWords(s, "", titleWord)
+}
+
+// ToCamelCase 转换为 camelCase，如 http_server -> httpServer
+func ToCamelCase(s string) string {
+	return joinWords(s, "", func(i int, w string) string {
+		if i == 0 {
+			return strings.ToLower(w)
+		}
+		return titleWord(i, w)
+	})
+}
+
+// Transliterator 将单个字符转写为 ASCII，ok 为false表示不处理该字符
+type Transliterator func(r rune) (out string, ok bool)
+
+var (
+	translitMu sync.RWMutex
+	translits  = []Transliterator{latinTransliterator}
+)
+
+// RegisterTransliterator 注册 Slugify 使用的转写函数（如汉字转拼音），后注册的优先
+func RegisterTransliterator(t Transliterator) {
+	translitMu.Lock()
+	defer translitMu.Unlock()
+	translits = append([]Transliterator{t}, translits...)
+}
+
+// latinDiacritics 常见带变音符号的拉丁字母
+var latinDiacritics = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+func latinTransliterator(r rune) (string, bool) {
+	out, ok := latinDiacritics[unicode.ToLower(r)]
+	return out, ok
+}
+
+// Slugify 生成URL友好的 slug：转为小写，依次尝试已注册的转写函数，
+// 字母和数字保留（未被转写的非拉丁字母如汉字也会保留），其他字符合并为一个连字符
+func Slugify(s string) string {
+	translitMu.RLock()
+	ts := translits
+	translitMu.RUnlock()
+
+	var b strings.Builder
+	dash := false
+	write := func(str string) {
+		for _, r := range str {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				if dash && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				dash = false
+				b.WriteRune(unicode.ToLower(r))
+			} else if !unicode.In(r, unicode.Mn, unicode.Me) {
+				dash = true
+			}
+		}
+	}
+	for _, r := range s {
+		done := false
+		for _, t := range ts {
+			if out, ok := t(r); ok {
+				write(out)
+				done = true
+				break
+			}
+		}
+		if !done {
+			write(string(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package gtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCase(t *testing.T) {
+	if !reflect.DeepEqual(SplitWords("HTTPServer_v2Api-ID"), []string{"HTTP", "Server", "v2", "Api", "ID"}) {
+		t.Fatal("SplitWords error")
+	}
+	cases := []struct {
+		in, snake, kebab, camel, pascal, screaming string
+	}{
+		{"HTTPServer", "http_server", "http-server", "httpServer", "HttpServer", "HTTP_SERVER"},
+		{"user_id", "user_id", "user-id", "userId", "UserId", "USER_ID"},
+		{"getURLForID", "get_url_for_id", "get-url-for-id", "getUrlForId", "GetUrlForId", "GET_URL_FOR_ID"},
+		{"Ünïcode wordÉtat", "ünïcode_word_état", "ünïcode-word-état", "ünïcodeWordÉtat", "ÜnïcodeWordÉtat", "ÜNÏCODE_WORD_ÉTAT"},
+	}
+	for _, c := range cases {
+		if ToSnakeCase(c.in) != c.snake || ToKebabCase(c.in) != c.kebab || ToCamelCase(c.in) != c.camel ||
+			ToPascalCase(c.in) != c.pascal || ToScreamingCase(c.in) != c.screaming {
+			t.Fatalf("case convert error: %q", c.in)
+		}
+	}
+
+	if Slugify("  Héllo, Wörld! -- Go 1.16 ") != "hello-world-go-1-16" {
+		t.Fatalf("Slugify error: %q", Slugify("  Héllo, Wörld! -- Go 1.16 "))
+	}
+	if Slugify("你好 世界") != "你好-世界" {
+		t.Fatal("Slugify should keep CJK letters")
+	}
+	translitMu.Lock()
+	saved := translits
+	translitMu.Unlock()
+	defer func() {
+		translitMu.Lock()
+		translits = saved
+		translitMu.Unlock()
+	}()
+	RegisterTransliterator(func(r rune) (string, bool) {
+		if r == '你' {
+			return "ni", true
+		}
+		return "", false
+	})
+	if Slugify("你") != "ni" {
+		t.Fatal("Slugify transliterator error")
+	}
+}
@@ -0,0 +1,202 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package charset
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encoding 文本编码名称
+type Encoding string
+
+const (
+	// Auto 自动检测编码（仅用于读取）
+	Auto Encoding = ""
+	// Unknown 无法识别的编码
+	Unknown Encoding = "unknown"
+
+	UTF8    Encoding = "UTF-8"
+	UTF16LE Encoding = "UTF-16LE"
+	UTF16BE Encoding = "UTF-16BE"
+	GBK     Encoding = "GBK"
+	GB18030 Encoding = "GB18030"
+	Big5    Encoding = "Big5"
+)
+
+// 不支持的编码
+var ErrUnsupported = errors.New("unsupported encoding")
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// codec 返回编码对应的编解码器，UTF-8、UTF-16 解码时会去除BOM
+func (e Encoding) codec() (encoding.Encoding, error) {
+	switch e {
+	case UTF8:
+		return unicode.UTF8BOM, nil
+	case UTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case UTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case GBK:
+		return simplifiedchinese.GBK, nil
+	case GB18030:
+		return simplifiedchinese.GB18030, nil
+	case Big5:
+		return traditionalchinese.Big5, nil
+	}
+	return nil, ErrUnsupported
+}
+
+// DetectEncoding 启发式检测文本编码：依次检查BOM、UTF-8 合法性、GBK/GB18030 字节范围，
+// 均不满足时返回 Unknown。注意 Big5 与 GBK 字节范围重叠，无法可靠区分，不做检测
+func DetectEncoding(data []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return UTF8
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return UTF16LE
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return UTF16BE
+	case utf8.Valid(data):
+		return UTF8
+	}
+
+	four := false
+	for i := 0; i < len(data); {
+		c := data[i]
+		if c < 0x80 {
+			i++
+			continue
+		}
+		if c == 0x80 || c == 0xFF || i+1 >= len(data) {
+			return Unknown
+		}
+		c2 := data[i+1]
+		// GB18030 四字节：[81-FE][30-39][81-FE][30-39]
+		if c2 >= 0x30 && c2 <= 0x39 {
+			if i+3 >= len(data) {
+				return Unknown
+			}
+			c3, c4 := data[i+2], data[i+3]
+			if c3 < 0x81 || c3 > 0xFE || c4 < 0x30 || c4 > 0x39 {
+				return Unknown
+			}
+			four = true
+			i += 4
+			continue
+		}
+		// GBK 双字节：[81-FE][40-7E,80-FE]
+		if c2 < 0x40 || c2 == 0x7F || c2 == 0xFF {
+			return Unknown
+		}
+		i += 2
+	}
+	if four {
+		return GB18030
+	}
+	return GBK
+}
+
+// NewReader 返回一个将 enc 编码的 r 流式转换为 UTF-8 的 Reader
+func NewReader(r io.Reader, enc Encoding) (io.Reader, error) {
+	c, err := enc.codec()
+	if err != nil {
+		return nil, err
+	}
+	return transform.NewReader(r, c.NewDecoder()), nil
+}
+
+// NewWriter 返回一个将写入的 UTF-8 文本流式转换为 enc 编码后写入 w 的 WriteCloser，
+// 写入完毕必须调用 Close 以刷新缓冲
+func NewWriter(w io.Writer, enc Encoding) (io.WriteCloser, error) {
+	c, err := enc.codec()
+	if err != nil {
+		return nil, err
+	}
+	if enc == UTF8 {
+		// 编码 UTF-8 时不添加BOM
+		c = unicode.UTF8
+	}
+	return transform.NewWriter(w, c.NewEncoder()), nil
+}
+
+// Decode 将 enc 编码的 data 转换为 UTF-8 字符串，enc 为 Auto 时自动检测
+func Decode(data []byte, enc Encoding) (string, error) {
+	if enc == Auto {
+		enc = DetectEncoding(data)
+	}
+	c, err := enc.codec()
+	if err != nil {
+		return "", err
+	}
+	raw, _, err := transform.Bytes(c.NewDecoder(), data)
+	return string(raw), err
+}
+
+// Encode 将 UTF-8 字符串转换为 enc 编码
+func Encode(text string, enc Encoding) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, enc)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(w, text); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FileReadStrEncoding 读取 enc 编码的文件并返回 UTF-8 字符串，enc 为 Auto 时自动检测
+func FileReadStrEncoding(path string, enc Encoding) (string, error) {
+	if enc == Auto {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return Decode(raw, enc)
+	}
+
+	fi, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fi.Close()
+	r, err := NewReader(fi, enc)
+	if err != nil {
+		return "", err
+	}
+	raw, err := ioutil.ReadAll(r)
+	return string(raw), err
+}
@@ -0,0 +1,83 @@
+package charset
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestCharset(t *testing.T) {
+	text := "你好，世界 hello"
+
+	gbk, err := Encode(text, GBK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if DetectEncoding(gbk) != GBK {
+		t.Fatal("detect gbk error")
+	}
+	s, err := Decode(gbk, Auto)
+	if err != nil || s != text {
+		t.Fatal("decode gbk error")
+	}
+
+	gb, err := Encode("€ 𠀀", GB18030)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if DetectEncoding(gb) != GB18030 {
+		t.Fatal("detect gb18030 error")
+	}
+
+	if DetectEncoding([]byte(text)) != UTF8 {
+		t.Fatal("detect utf8 error")
+	}
+	bom := append([]byte{0xEF, 0xBB, 0xBF}, text...)
+	if DetectEncoding(bom) != UTF8 {
+		t.Fatal("detect utf8 bom error")
+	}
+	s, _ = Decode(bom, Auto)
+	if s != text {
+		t.Fatal("utf8 bom should be stripped")
+	}
+	if DetectEncoding([]byte{0xFF, 0xFE, 'a', 0}) != UTF16LE {
+		t.Fatal("detect utf16le error")
+	}
+	if DetectEncoding([]byte{0xC4, 0x7F}) != Unknown {
+		t.Fatal("invalid bytes should be unknown")
+	}
+
+	big5, err := Encode("繁體", Big5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReader(bytes.NewReader(big5), Big5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := ioutil.ReadAll(r)
+	if string(raw) != "繁體" {
+		t.Fatal("big5 reader error")
+	}
+
+	if _, err = NewReader(nil, "latin1"); err != ErrUnsupported {
+		t.Fatal("should be unsupported")
+	}
+
+	f, err := ioutil.TempFile("", "charset-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	w, _ := NewWriter(f, GBK)
+	w.Write([]byte(text))
+	w.Close()
+	f.Close()
+	for _, enc := range []Encoding{GBK, Auto} {
+		s, err = FileReadStrEncoding(f.Name(), enc)
+		if err != nil || s != text {
+			t.Fatalf("FileReadStrEncoding(%q) error", enc)
+		}
+	}
+}
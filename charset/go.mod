@@ -0,0 +1,5 @@
+module tcw.im/gtc/charset
+
+go 1.13
+
+require golang.org/x/text v0.14.0
@@ -0,0 +1,328 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// gtc 命令行工具，以子命令的形式提供 gtc 库中的常用函数，语义与库完全一致。
+//
+// 用法：
+//
+//	gtc [-json] <command> [arguments]
+//
+// 子命令：
+//
+//	md5 <file...>                   计算文件MD5值（MD5File）
+//	copy [-n bytes] <src> <dst>     复制文件（FileCopy、FileCopyN）
+//	exists <path...>                判断路径是否存在（PathExist）
+//	isdir <path...>                 判断路径是否为目录（IsDir）
+//	bool <value>                    判断值是否为真（IsTrue）
+//	substr [-mode m] [-loose] <str> <start> <end>
+//	                                截取字符串（SubString，默认与 SubStr 相同）
+//
+// 退出码：0 成功或判断为真，1 判断为假，2 参数错误，3 执行出错。
+// 使用 -json 时结果以JSON格式输出到标准输出，错误信息也包含在JSON中。
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"tcw.im/gtc"
+)
+
+// 退出码
+const (
+	exitOK    = 0
+	exitFalse = 1
+	exitUsage = 2
+	exitError = 3
+)
+
+// errUsage 表示参数错误，子命令返回它时会打印用法
+var errUsage = errors.New("invalid arguments")
+
+// env 是子命令的运行环境
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	json   bool
+}
+
+// command 子命令，run 返回退出码
+type command struct {
+	usage string
+	run   func(e *env, args []string) int
+}
+
+var commands map[string]command
+
+// 在 init 中注册，避免子命令引用 commands 时产生初始化循环
+func init() {
+	commands = map[string]command{
+		"md5":    {"md5 <file...>", runMD5},
+		"copy":   {"copy [-n bytes] <src> <dst>", runCopy},
+		"exists": {"exists <path...>", runExists},
+		"isdir":  {"isdir <path...>", runIsDir},
+		"bool":   {"bool <value>", runBool},
+		"substr": {"substr [-mode rune|byte|grapheme] [-loose] <str> <start> <end>", runSubStr},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 解析全局参数并执行子命令，返回退出码
+func run(args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet("gtc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&e.json, "json", false, "output result as JSON")
+	fs.Usage = func() { usage(stderr) }
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "gtc: unknown command %q\n", fs.Arg(0))
+		usage(stderr)
+		return exitUsage
+	}
+	return cmd.run(e, fs.Args()[1:])
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "usage: gtc [-json] <command> [arguments]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, name := range names {
+		fmt.Fprintln(w, "  "+commands[name].usage)
+	}
+	fmt.Fprintln(w, "\nexit status: 0 ok/true, 1 false, 2 usage error, 3 failure")
+}
+
+// flagSet 创建子命令的参数解析器
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	return fs
+}
+
+// usageError 打印子命令用法并返回参数错误的退出码
+func (e *env) usageError(name string, err error) int {
+	if err != nil && err != errUsage {
+		fmt.Fprintf(e.stderr, "gtc %s: %v\n", name, err)
+	}
+	fmt.Fprintln(e.stderr, "usage: gtc "+commands[name].usage)
+	return exitUsage
+}
+
+// fail 输出错误信息并返回执行出错的退出码
+func (e *env) fail(name string, err error) int {
+	if e.json {
+		e.writeJSON(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintf(e.stderr, "gtc %s: %v\n", name, err)
+	}
+	return exitError
+}
+
+func (e *env) writeJSON(v interface{}) {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+type md5Result struct {
+	File  string `json:"file"`
+	MD5   string `json:"md5,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// runMD5 输出格式与 md5sum 相同，任一文件出错时退出码为3
+func runMD5(e *env, args []string) int {
+	if len(args) == 0 {
+		return e.usageError("md5", errUsage)
+	}
+	code := exitOK
+	results := make([]md5Result, 0, len(args))
+	for _, file := range args {
+		r := md5Result{File: file}
+		sum, err := gtc.MD5File(file)
+		if err != nil {
+			r.Error = err.Error()
+			code = exitError
+			if !e.json {
+				fmt.Fprintf(e.stderr, "gtc md5: %s: %v\n", file, err)
+			}
+		} else {
+			r.MD5 = sum
+			if !e.json {
+				fmt.Fprintf(e.stdout, "%s  %s\n", sum, file)
+			}
+		}
+		results = append(results, r)
+	}
+	if e.json {
+		e.writeJSON(results)
+	}
+	return code
+}
+
+func runCopy(e *env, args []string) int {
+	fs := e.flagSet("copy")
+	n := fs.Int64("n", -1, "copy only the first n bytes")
+	if err := fs.Parse(args); err != nil {
+		return e.usageError("copy", err)
+	}
+	if fs.NArg() != 2 {
+		return e.usageError("copy", errUsage)
+	}
+	src, dst := fs.Arg(0), fs.Arg(1)
+	// 经临时文件写入后替换 dst，确保覆盖时旧内容被完整替换（而不是残留尾部）
+	var transform gtc.CopyTransform
+	if *n >= 0 {
+		transform = func(w io.Writer, r io.Reader) (int64, error) {
+			return io.CopyN(w, r, *n)
+		}
+	}
+	written, err := gtc.FileCopyWith(dst, src, transform)
+	if err != nil {
+		return e.fail("copy", err)
+	}
+	if e.json {
+		e.writeJSON(map[string]interface{}{"src": src, "dst": dst, "written": written})
+	}
+	return exitOK
+}
+
+type checkResult struct {
+	Path   string `json:"path"`
+	Result bool   `json:"result"`
+}
+
+// runCheck 对每个路径执行 check，全部为真时退出码为0，否则为1。
+// 单个路径时输出 true/false，多个路径时每行输出结果和路径
+func runCheck(e *env, name string, args []string, check func(string) bool) int {
+	if len(args) == 0 {
+		return e.usageError(name, errUsage)
+	}
+	code := exitOK
+	results := make([]checkResult, len(args))
+	for i, path := range args {
+		ok := check(path)
+		if !ok {
+			code = exitFalse
+		}
+		results[i] = checkResult{path, ok}
+	}
+	switch {
+	case e.json:
+		e.writeJSON(results)
+	case len(results) == 1:
+		fmt.Fprintln(e.stdout, results[0].Result)
+	default:
+		for _, r := range results {
+			fmt.Fprintf(e.stdout, "%t\t%s\n", r.Result, r.Path)
+		}
+	}
+	return code
+}
+
+func runExists(e *env, args []string) int {
+	return runCheck(e, "exists", args, gtc.PathExist)
+}
+
+func runIsDir(e *env, args []string) int {
+	return runCheck(e, "isdir", args, gtc.IsDir)
+}
+
+// runBool 按 IsTrue 判断，为真时退出码为0，否则为1；JSON 中 recognized 表示值能否被识别
+func runBool(e *env, args []string) int {
+	if len(args) != 1 {
+		return e.usageError("bool", errUsage)
+	}
+	value, recognized := gtc.ParseBool(args[0])
+	result := recognized && value
+	if e.json {
+		e.writeJSON(map[string]interface{}{"value": args[0], "result": result, "recognized": recognized})
+	} else {
+		fmt.Fprintln(e.stdout, result)
+	}
+	if result {
+		return exitOK
+	}
+	return exitFalse
+}
+
+var subStrModes = map[string]gtc.SubStrMode{
+	"rune":     gtc.RuneMode,
+	"byte":     gtc.ByteMode,
+	"grapheme": gtc.GraphemeMode,
+}
+
+// runSubStr 默认按 rune 计数、使用 SubString 的严格模式：索引超出范围时报错退出
+// （不同于 SubStr 返回空字符串），负数索引表示从末尾倒数；-loose 时自动截断到有效范围。
+// 负数索引需放在 -- 之后以免被当作参数
+func runSubStr(e *env, args []string) int {
+	fs := e.flagSet("substr")
+	mode := fs.String("mode", "rune", "count by rune, byte or grapheme")
+	loose := fs.Bool("loose", false, "clamp out of range indices instead of failing")
+	if err := fs.Parse(args); err != nil {
+		return e.usageError("substr", err)
+	}
+	m, ok := subStrModes[*mode]
+	if !ok {
+		return e.usageError("substr", fmt.Errorf("unknown mode %q", *mode))
+	}
+	if fs.NArg() != 3 {
+		return e.usageError("substr", errUsage)
+	}
+	start, err := strconv.Atoi(fs.Arg(1))
+	if err != nil {
+		return e.usageError("substr", fmt.Errorf("invalid start %q", fs.Arg(1)))
+	}
+	end, err := strconv.Atoi(fs.Arg(2))
+	if err != nil {
+		return e.usageError("substr", fmt.Errorf("invalid end %q", fs.Arg(2)))
+	}
+	s, err := gtc.SubString(fs.Arg(0), start, end, gtc.SubStrOption{Mode: m, Strict: !*loose})
+	if err != nil {
+		return e.fail("substr", err)
+	}
+	if e.json {
+		e.writeJSON(map[string]string{"result": s})
+	} else {
+		fmt.Fprintln(e.stdout, s)
+	}
+	return exitOK
+}
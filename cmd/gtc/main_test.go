@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"tcw.im/gtc"
+)
+
+func runArgs(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRun(t *testing.T) {
+	if code, _, _ := runArgs(); code != exitUsage {
+		t.Fatal("no command should be usage error")
+	}
+	if code, _, stderr := runArgs("frob"); code != exitUsage || !strings.Contains(stderr, "unknown command") {
+		t.Fatal("unknown command error")
+	}
+	if code, _, _ := runArgs("-h"); code != exitOK {
+		t.Fatal("help should exit 0")
+	}
+}
+
+func TestMD5AndCopy(t *testing.T) {
+	ws, err := gtc.TempWorkspace("gtc-cmd-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("a.txt", []byte("hello"))
+	src, dst := ws.Path("a.txt"), ws.Path("b.txt")
+
+	code, stdout, _ := runArgs("md5", src)
+	if code != exitOK || stdout != gtc.MD5("hello")+"  "+src+"\n" {
+		t.Fatalf("md5 output error: %q", stdout)
+	}
+	code, stdout, _ = runArgs("-json", "md5", src, ws.Path("none"))
+	var results []md5Result
+	if code != exitError || json.Unmarshal([]byte(stdout), &results) != nil {
+		t.Fatal("md5 json error")
+	}
+	if len(results) != 2 || results[0].MD5 != gtc.MD5("hello") || results[1].Error == "" {
+		t.Fatalf("md5 json results error: %+v", results)
+	}
+
+	// 覆盖已存在的更长文件时不应残留旧内容
+	ws.WriteFile("b.txt", []byte("old content"))
+	if code, _, _ = runArgs("copy", "-n", "2", src, dst); code != exitOK {
+		t.Fatal("copy -n error")
+	}
+	if s, _ := gtc.FileReadStr(dst); s != "he" {
+		t.Fatalf("copy -n content error: %q", s)
+	}
+	code, stdout, _ = runArgs("-json", "copy", ws.Path("none"), dst)
+	if code != exitError || !strings.Contains(stdout, `"error"`) {
+		t.Fatal("copy missing src should fail")
+	}
+	if code, _, _ = runArgs("copy", src); code != exitUsage {
+		t.Fatal("copy usage error")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	code, stdout, _ := runArgs("exists", ".")
+	if code != exitOK || stdout != "true\n" {
+		t.Fatal("exists error")
+	}
+	code, stdout, _ = runArgs("isdir", ".", "main.go")
+	if code != exitFalse || stdout != "true\t.\nfalse\tmain.go\n" {
+		t.Fatalf("isdir error: %q", stdout)
+	}
+	code, stdout, _ = runArgs("-json", "exists", "none")
+	if code != exitFalse || stdout != `[{"path":"none","result":false}]`+"\n" {
+		t.Fatalf("exists json error: %q", stdout)
+	}
+}
+
+func TestBool(t *testing.T) {
+	for _, v := range []string{"1", "yes", "是"} {
+		if code, _, _ := runArgs("bool", v); code != exitOK {
+			t.Fatalf("bool %s should be true", v)
+		}
+	}
+	for _, v := range []string{"0", "off", "unknown"} {
+		if code, _, _ := runArgs("bool", v); code != exitFalse {
+			t.Fatalf("bool %s should be false", v)
+		}
+	}
+	_, stdout, _ := runArgs("-json", "bool", "unknown")
+	if stdout != `{"recognized":false,"result":false,"value":"unknown"}`+"\n" {
+		t.Fatalf("bool json error: %q", stdout)
+	}
+}
+
+func TestSubStr(t *testing.T) {
+	code, stdout, _ := runArgs("substr", "你好世界", "1", "3")
+	if code != exitOK || stdout != "好世\n" {
+		t.Fatal("substr error")
+	}
+	if code, _, _ = runArgs("substr", "abc", "1", "9"); code != exitError {
+		t.Fatal("substr out of range should fail in strict mode")
+	}
+	code, stdout, _ = runArgs("-json", "substr", "-loose", "-mode", "byte", "--", "abc", "-2", "9")
+	if code != exitOK || stdout != `{"result":"bc"}`+"\n" {
+		t.Fatalf("substr loose error: %q", stdout)
+	}
+	if code, _, _ = runArgs("substr", "-mode", "word", "abc", "0", "1"); code != exitUsage {
+		t.Fatal("substr unknown mode")
+	}
+	if code, _, _ = runArgs("substr", "abc", "x", "1"); code != exitUsage {
+		t.Fatal("substr invalid index")
+	}
+}
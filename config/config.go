@@ -0,0 +1,334 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package config 分层配置加载器，支持 .env、INI、JSON 文件。
+//
+// 合并顺序（后者覆盖前者）：默认值 < 配置文件（按添加顺序）< 环境变量 < Set 设置的值。
+//
+// 所有键都是扁平的字符串，INI 的键为 section.key，JSON 的嵌套对象以点号连接。
+// 值中的 ${NAME} 会被替换为配置项 NAME 的值，配置项不存在时使用同名环境变量。
+package config
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tcw.im/gtc"
+)
+
+var (
+	// 无法根据扩展名识别的配置文件格式
+	ErrUnknownFormat = errors.New("unknown config file format")
+	// Watch 的检查间隔必须大于0
+	ErrInvalidInterval = errors.New("watch interval must be positive")
+)
+
+// interpolation 最大嵌套深度，防止循环引用
+const maxExpandDepth = 10
+
+type source struct {
+	path     string
+	optional bool
+	modTime  time.Time
+}
+
+// Config 分层配置，并发安全
+type Config struct {
+	mu        sync.RWMutex
+	defaults  map[string]string
+	overrides map[string]string
+	// raw 合并后、展开引用前的值
+	raw       map[string]string
+	values    map[string]string
+	files     []*source
+	envPrefix string
+	stop      chan struct{}
+}
+
+// New 创建一个空配置，添加文件、默认值后需调用 Load 加载
+func New() *Config {
+	return &Config{
+		defaults:  make(map[string]string),
+		overrides: make(map[string]string),
+		raw:       make(map[string]string),
+		values:    make(map[string]string),
+	}
+}
+
+// SetDefault 设置默认值，优先级最低
+func (c *Config) SetDefault(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaults[key] = value
+}
+
+// AddFile 添加配置文件，格式由扩展名决定：.json 为 JSON，.ini/.conf/.cfg 为 INI，
+// .env 或以 .env 开头的文件为 dotenv。optional 为true时文件不存在不会报错
+func (c *Config) AddFile(path string, optional bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files = append(c.files, &source{path: path, optional: optional})
+}
+
+// SetEnvPrefix 设置环境变量前缀，键 a.b-c 对应的环境变量为 PREFIX_A_B_C
+func (c *Config) SetEnvPrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.envPrefix = prefix
+}
+
+// Set 设置覆盖值，优先级最高，立即生效（包括其他配置项中对它的 ${NAME} 引用）
+func (c *Config) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides[key] = value
+	c.raw[key] = value
+	c.expandAll()
+}
+
+// expandAll 根据 raw 重新计算展开引用后的值
+func (c *Config) expandAll() {
+	values := make(map[string]string, len(c.raw))
+	for k, v := range c.raw {
+		values[k] = expand(v, c.raw, 0)
+	}
+	c.values = values
+}
+
+// envName 返回键对应的环境变量名
+func (c *Config) envName(key string) string {
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if c.envPrefix != "" {
+		name = c.envPrefix + "_" + name
+	}
+	return name
+}
+
+// Load 按顺序读取、合并所有配置层并展开 ${NAME} 引用
+func (c *Config) Load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	merged := make(map[string]string)
+	for k, v := range c.defaults {
+		merged[k] = v
+	}
+	// 全部解析成功后才更新修改时间，否则 Watch 会认为出错的文件已加载而不再重试
+	modTimes := make([]time.Time, len(c.files))
+	for i, src := range c.files {
+		if gtc.PathNotExist(src.path) {
+			if src.optional {
+				continue
+			}
+			return &os.PathError{Op: "open", Path: src.path, Err: os.ErrNotExist}
+		}
+		if stat, err := os.Stat(src.path); err == nil {
+			modTimes[i] = stat.ModTime()
+		}
+		data, err := parseFile(src.path)
+		if err != nil {
+			return err
+		}
+		for k, v := range data {
+			merged[k] = v
+		}
+	}
+	for k := range merged {
+		if v, ok := os.LookupEnv(c.envName(k)); ok {
+			merged[k] = v
+		}
+	}
+	for k, v := range c.overrides {
+		merged[k] = v
+	}
+
+	for i, src := range c.files {
+		src.modTime = modTimes[i]
+	}
+	c.raw = merged
+	c.expandAll()
+	return nil
+}
+
+// expand 展开 ${NAME} 引用，先查找配置项，再查找环境变量，均不存在时替换为空字符串
+func expand(s string, values map[string]string, depth int) string {
+	if depth >= maxExpandDepth || !strings.Contains(s, "${") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+		name := s[start+2 : start+end]
+		if v, ok := values[name]; ok {
+			b.WriteString(expand(v, values, depth+1))
+		} else {
+			b.WriteString(os.Getenv(name))
+		}
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// Lookup 获取配置项，未加载的键会尝试读取对应的环境变量
+func (c *Config) Lookup(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if v, ok := c.values[key]; ok {
+		return v, true
+	}
+	return os.LookupEnv(c.envName(key))
+}
+
+// Has 判断配置项是否存在
+func (c *Config) Has(key string) bool {
+	_, ok := c.Lookup(key)
+	return ok
+}
+
+// Keys 返回所有已加载的键
+func (c *Config) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// GetString 获取字符串，不存在时返回空字符串
+func (c *Config) GetString(key string) string {
+	v, _ := c.Lookup(key)
+	return v
+}
+
+// GetBool 获取布尔值，按 gtc.IsTrue 解析
+func (c *Config) GetBool(key string) bool {
+	return gtc.IsTrue(c.GetString(key))
+}
+
+// GetInt 获取整数，不存在或无效时返回0
+func (c *Config) GetInt(key string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(c.GetString(key)))
+	return n
+}
+
+// GetFloat 获取浮点数，不存在或无效时返回0
+func (c *Config) GetFloat(key string) float64 {
+	n, _ := strconv.ParseFloat(strings.TrimSpace(c.GetString(key)), 64)
+	return n
+}
+
+// GetDuration 获取时间间隔（如 1m30s、3d4h），按 gtc.ParseDuration 解析，不存在或无效时返回0
+func (c *Config) GetDuration(key string) time.Duration {
+	d, _ := gtc.ParseDuration(c.GetString(key))
+	return d
+}
+
+// GetBytes 获取字节大小（如 512MiB、1.5GB），按 gtc.ParseBytes 解析，不存在或无效时返回0
+func (c *Config) GetBytes(key string) int64 {
+	n, _ := gtc.ParseBytes(c.GetString(key))
+	return n
+}
+
+// GetStrings 获取以逗号分隔的字符串切片，会去除每项的首尾空白
+func (c *Config) GetStrings(key string) []string {
+	v := c.GetString(key)
+	if v == "" {
+		return nil
+	}
+	items := strings.Split(v, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// Watch 每隔 interval 检查配置文件的修改时间，有变化时重新加载，
+// 重新加载后调用 onChange（可为nil，参数为 Load 的错误）。调用 Close 停止监听。
+// interval 不大于0时返回 ErrInvalidInterval，已在监听时重复调用无效果
+func (c *Config) Watch(interval time.Duration, onChange func(error)) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	c.mu.Lock()
+	if c.stop != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	stop := make(chan struct{})
+	c.stop = stop
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !c.changed() {
+					continue
+				}
+				err := c.Load()
+				if onChange != nil {
+					onChange(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// changed 判断配置文件是否有修改（含新建、删除）
+func (c *Config) changed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, src := range c.files {
+		var mt time.Time
+		if stat, err := os.Stat(src.path); err == nil {
+			mt = stat.ModTime()
+		}
+		if !mt.Equal(src.modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close 停止 Watch
+func (c *Config) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtc-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	env := filepath.Join(dir, ".env")
+	ini := filepath.Join(dir, "app.ini")
+	js := filepath.Join(dir, "app.json")
+	ioutil.WriteFile(env, []byte("# comment\nexport name=gtc\ndebug='off'\nhome=\"${HOME}/data\" # inline\n"), 0644)
+	ioutil.WriteFile(ini, []byte("debug = yes\n[redis]\nurl: redis://${redis.host}:6379\nhost = 127.0.0.1\n"), 0644)
+	ioutil.WriteFile(js, []byte(`{"server": {"port": 8080, "tags": ["a", "b"]}, "ratio": 0.5, "timeout": "1d3s", "max_size": "1.5GiB"}`), 0644)
+
+	os.Setenv("GTCTEST_SERVER_PORT", "9090")
+	defer os.Unsetenv("GTCTEST_SERVER_PORT")
+	os.Setenv("GTCTEST_EXTRA", "x")
+	defer os.Unsetenv("GTCTEST_EXTRA")
+
+	c := New()
+	c.SetEnvPrefix("GTCTEST")
+	c.SetDefault("name", "default")
+	c.SetDefault("level", "info")
+	c.AddFile(env, false)
+	c.AddFile(ini, false)
+	c.AddFile(js, false)
+	c.AddFile(filepath.Join(dir, "missing.json"), true)
+	c.Set("level", "debug")
+	if err = c.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.GetString("name") != "gtc" || c.GetString("level") != "debug" {
+		t.Fatal("layer order error")
+	}
+	if !c.GetBool("debug") {
+		t.Fatal("ini should override dotenv")
+	}
+	if c.GetString("home") != os.Getenv("HOME")+"/data" {
+		t.Fatal("env interpolation error")
+	}
+	if c.GetString("redis.url") != "redis://127.0.0.1:6379" {
+		t.Fatal("key interpolation error")
+	}
+	if c.GetInt("server.port") != 9090 {
+		t.Fatal("env should override file")
+	}
+	if c.GetFloat("ratio") != 0.5 || c.GetDuration("timeout") != 24*time.Hour+3*time.Second {
+		t.Fatal("typed getter error")
+	}
+	if c.GetBytes("max_size") != 3<<29 {
+		t.Fatal("typed getter error")
+	}
+	if tags := c.GetStrings("server.tags"); len(tags) != 2 || tags[1] != "b" {
+		t.Fatal("json array error")
+	}
+	if c.GetString("extra") != "x" || c.Has("nothing") {
+		t.Fatal("env lookup error")
+	}
+
+	c.Set("base", "/srv")
+	c.Set("dir", "${base}/data")
+	if c.GetString("dir") != "/srv/data" {
+		t.Fatal("Set should expand references")
+	}
+	c.Set("redis.host", "10.0.0.1")
+	if c.GetString("redis.url") != "redis://10.0.0.1:6379" {
+		t.Fatal("Set should update references to it")
+	}
+
+	c.AddFile(filepath.Join(dir, "missing.ini"), false)
+	if c.Load() == nil {
+		t.Fatal("missing required file should raise error")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	f, err := ioutil.TempFile("", "gtc-watch*.env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("v=1\n")
+	f.Close()
+
+	c := New()
+	c.AddFile(f.Name(), false)
+	if err = c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Watch(0, nil) != ErrInvalidInterval {
+		t.Fatal("watch with zero interval should fail")
+	}
+	done := make(chan error, 1)
+	err = c.Watch(10*time.Millisecond, func(err error) {
+		// 测试未及时读取时丢弃，避免阻塞监听协程
+		select {
+		case done <- err:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// 先写临时文件再重命名，避免监听协程读到写了一半的文件
+	write := func(content string, mt time.Time) {
+		ioutil.WriteFile(f.Name()+".tmp", []byte(content), 0644)
+		os.Chtimes(f.Name()+".tmp", mt, mt)
+		os.Rename(f.Name()+".tmp", f.Name())
+	}
+	wait := func(ok func(error) bool) bool {
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case err := <-done:
+				if ok(err) {
+					return true
+				}
+			case <-timeout:
+				return false
+			}
+		}
+	}
+
+	mt := time.Now().Add(time.Second)
+	write("v=2\n", mt)
+	if !wait(func(err error) bool { return err == nil && c.GetInt("v") == 2 }) {
+		t.Fatal("hot reload error")
+	}
+
+	// 解析失败时不记录修改时间，修复后即使修改时间相同也会重新加载
+	mt = mt.Add(time.Second)
+	write("novalue\n", mt)
+	if !wait(func(err error) bool { return err != nil }) || c.GetInt("v") != 2 {
+		t.Fatal("invalid file should report error and keep old values")
+	}
+	write("v=3\n", mt)
+	if !wait(func(err error) bool { return err == nil && c.GetInt("v") == 3 }) {
+		t.Fatal("fixed file should be reloaded")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := ParseDotEnv("novalue"); err == nil {
+		t.Fatal("invalid dotenv line")
+	}
+	if _, err := ParseINI("[section"); err == nil {
+		t.Fatal("invalid ini section")
+	}
+	if _, err := parseFile("config.go"); err != ErrUnknownFormat {
+		t.Fatal("unknown format")
+	}
+}
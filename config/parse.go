@@ -0,0 +1,164 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tcw.im/gtc"
+)
+
+// parseFile 根据扩展名解析配置文件
+func parseFile(path string) (map[string]string, error) {
+	text, err := gtc.FileReadStr(path)
+	if err != nil {
+		return nil, err
+	}
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(base, ".json"):
+		return ParseJSON(text)
+	case strings.HasSuffix(base, ".ini"), strings.HasSuffix(base, ".conf"), strings.HasSuffix(base, ".cfg"):
+		return ParseINI(text)
+	case strings.HasSuffix(base, ".env"), strings.HasPrefix(base, ".env"):
+		return ParseDotEnv(text)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// unquote 去除值两端成对的单引号或双引号，双引号内支持 \n \t \" \\ 转义
+func unquote(v string) string {
+	if len(v) >= 2 {
+		if v[0] == '\'' && v[len(v)-1] == '\'' {
+			return v[1 : len(v)-1]
+		}
+		if v[0] == '"' && v[len(v)-1] == '"' {
+			return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(v[1 : len(v)-1])
+		}
+	}
+	return v
+}
+
+// stripComment 去除值中以空白加#开始的行内注释，加引号的值保留到闭合引号为止
+func stripComment(v string) string {
+	if strings.HasPrefix(v, `"`) || strings.HasPrefix(v, "'") {
+		if i := strings.LastIndex(v, v[:1]); i > 0 {
+			return v[:i+1]
+		}
+		return v
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
+
+// ParseDotEnv 解析 .env 格式：每行一个 KEY=VALUE，支持 export 前缀、引号和#注释
+func ParseDotEnv(text string) (map[string]string, error) {
+	data := make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("dotenv: invalid line %d: %q", i+1, line)
+		}
+		data[strings.TrimSpace(kv[0])] = unquote(stripComment(strings.TrimSpace(kv[1])))
+	}
+	return data, nil
+}
+
+// ParseINI 解析 INI 格式：[section] 下的键为 section.key，支持 = 或 : 分隔以及 # ; 注释
+func ParseINI(text string) (map[string]string, error) {
+	data := make(map[string]string)
+	section := ""
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("ini: invalid section at line %d: %q", i+1, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		idx := strings.IndexAny(line, "=:")
+		if idx <= 0 {
+			return nil, fmt.Errorf("ini: invalid line %d: %q", i+1, line)
+		}
+		key := strings.TrimSpace(line[:idx])
+		if section != "" {
+			key = section + "." + key
+		}
+		data[key] = unquote(stripComment(strings.TrimSpace(line[idx+1:])))
+	}
+	return data, nil
+}
+
+// ParseJSON 解析 JSON 对象，嵌套对象的键以点号连接，数组元素以逗号连接，null 为空字符串
+func ParseJSON(text string) (map[string]string, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	data := make(map[string]string)
+	flattenJSON("", obj, data)
+	return data, nil
+}
+
+func flattenJSON(prefix string, v interface{}, data map[string]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if prefix != "" {
+				k = prefix + "." + k
+			}
+			flattenJSON(k, item, data)
+		}
+	case []interface{}:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = jsonScalar(item)
+		}
+		data[prefix] = strings.Join(items, ",")
+	default:
+		data[prefix] = jsonScalar(val)
+	}
+}
+
+func jsonScalar(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]interface{}, []interface{}:
+		raw, _ := json.Marshal(val)
+		return string(raw)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,371 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// 加密相关错误
+var (
+	ErrInvalidKey     = errors.New("key must be 32 bytes for AES-256")
+	ErrNotEncrypted   = errors.New("data is not encrypted by gtc")
+	ErrSecretMismatch = errors.New("secret type does not match encrypted data")
+	ErrDecrypt        = errors.New("decrypt failed: data is tampered or secret is wrong")
+	ErrTruncated      = errors.New("encrypted data is truncated")
+	ErrTooLarge       = errors.New("data is too large to encrypt")
+)
+
+const (
+	cryptMagic      = "GTCE"
+	cryptVersion    = 1
+	cryptChunkSize  = 64 * 1024
+	cryptSaltSize   = 16
+	cryptNoncePre   = 7
+	cryptIterations = 200000
+
+	// kdfNone 直接使用原始密钥，仅用于解密旧数据
+	kdfNone   = 0
+	kdfPBKDF2 = 1
+	kdfHKDF   = 2
+)
+
+// cryptMaxChunks 最大块序号，块序号为32位，超过后 nonce 会重复（测试中可调小）
+var cryptMaxChunks uint32 = 1<<32 - 1
+
+// Secret 加密密钥，使用 KeySecret 或 PasswordSecret 创建
+type Secret struct {
+	key      []byte
+	password []byte
+}
+
+// KeySecret 使用32字节的原始密钥，每次加密都通过 HKDF-SHA256 和随机盐派生子密钥，
+// 因此同一密钥加密大量文件也不会有 nonce 重复的风险
+func KeySecret(key []byte) Secret {
+	return Secret{key: key}
+}
+
+// PasswordSecret 使用口令，实际密钥由 PBKDF2-HMAC-SHA256 和随机盐派生，盐保存在密文头部
+func PasswordSecret(password string) Secret {
+	return Secret{password: []byte(password)}
+}
+
+// pbkdf2 实现 RFC 8018 PBKDF2-HMAC-SHA256
+func pbkdf2(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var out []byte
+	for block := uint32(1); len(out) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], block)
+		prf.Write(b[:])
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iter; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		out = append(out, t...)
+	}
+	return out[:keyLen]
+}
+
+// hkdf 实现 RFC 5869 HKDF-SHA256，keyLen 不能超过 255*32
+func hkdf(secret, salt, info []byte, keyLen int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(secret)
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	var out, t []byte
+	for i := byte(1); len(out) < keyLen; i++ {
+		expand.Reset()
+		expand.Write(t)
+		expand.Write(info)
+		expand.Write([]byte{i})
+		t = expand.Sum(nil)
+		out = append(out, t...)
+	}
+	return out[:keyLen]
+}
+
+// hkdfInfo 派生子密钥时的上下文信息
+var hkdfInfo = []byte("gtc crypt v1")
+
+// chunkNonce 由随机前缀、块序号和是否最后一块组成，防止块被重排、截断
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[cryptNoncePre:], counter)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptStream 使用 AES-256-GCM 分块流式加密，每块64KiB并单独认证，内存占用与数据大小无关。
+// 返回写入 dst 的字节数
+func EncryptStream(dst io.Writer, src io.Reader, secret Secret) (written int64, err error) {
+	header := bytes.NewBufferString(cryptMagic)
+	header.WriteByte(cryptVersion)
+	if secret.password == nil && len(secret.key) != 32 {
+		return 0, ErrInvalidKey
+	}
+	salt := make([]byte, cryptSaltSize)
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return
+	}
+	var key []byte
+	if secret.password != nil {
+		header.WriteByte(kdfPBKDF2)
+		binary.Write(header, binary.BigEndian, uint32(cryptIterations))
+		header.Write(salt)
+		key = pbkdf2(secret.password, salt, cryptIterations, 32)
+	} else {
+		header.WriteByte(kdfHKDF)
+		header.Write(salt)
+		key = hkdf(secret.key, salt, hkdfInfo, 32)
+	}
+	prefix := make([]byte, cryptNoncePre)
+	if _, err = io.ReadFull(rand.Reader, prefix); err != nil {
+		return
+	}
+	header.Write(prefix)
+	aad := header.Bytes()
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return
+	}
+	n, err := dst.Write(aad)
+	written += int64(n)
+	if err != nil {
+		return
+	}
+
+	r := bufio.NewReaderSize(src, cryptChunkSize)
+	buf := make([]byte, cryptChunkSize)
+	out := make([]byte, 0, cryptChunkSize+aead.Overhead())
+	for counter := uint32(0); ; counter++ {
+		m, e := io.ReadFull(r, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return written, e
+		}
+		last := e != nil
+		if !last {
+			if _, e = r.Peek(1); e == io.EOF {
+				last = true
+			}
+		}
+		if !last && counter == cryptMaxChunks {
+			return written, ErrTooLarge
+		}
+		out = aead.Seal(out[:0], chunkNonce(prefix, counter, last), buf[:m], aad)
+		n, err = dst.Write(out)
+		written += int64(n)
+		if err != nil || last {
+			return
+		}
+	}
+}
+
+// DecryptStream 解密 EncryptStream 生成的数据，任何一块被篡改或数据被截断都会返回错误。
+// 返回写入 dst 的明文字节数
+func DecryptStream(dst io.Writer, src io.Reader, secret Secret) (written int64, err error) {
+	r := bufio.NewReaderSize(src, cryptChunkSize+64)
+	head := make([]byte, len(cryptMagic)+2)
+	if _, err = io.ReadFull(r, head); err != nil || string(head[:4]) != cryptMagic || head[4] != cryptVersion {
+		return 0, ErrNotEncrypted
+	}
+	header := bytes.NewBuffer(head)
+	key := secret.key
+	switch head[5] {
+	case kdfPBKDF2:
+		if secret.password == nil {
+			return 0, ErrSecretMismatch
+		}
+		params := make([]byte, 4+cryptSaltSize)
+		if _, err = io.ReadFull(r, params); err != nil {
+			return 0, ErrTruncated
+		}
+		header.Write(params)
+		// 迭代次数来自未经认证的头部，只接受固定值，防止伪造的头部耗尽CPU
+		if binary.BigEndian.Uint32(params[:4]) != cryptIterations {
+			return 0, ErrNotEncrypted
+		}
+		key = pbkdf2(secret.password, params[4:], cryptIterations, 32)
+	case kdfHKDF:
+		if secret.password != nil {
+			return 0, ErrSecretMismatch
+		}
+		salt := make([]byte, cryptSaltSize)
+		if _, err = io.ReadFull(r, salt); err != nil {
+			return 0, ErrTruncated
+		}
+		header.Write(salt)
+		if len(secret.key) != 32 {
+			return 0, ErrInvalidKey
+		}
+		key = hkdf(secret.key, salt, hkdfInfo, 32)
+	case kdfNone:
+		if secret.password != nil {
+			return 0, ErrSecretMismatch
+		}
+	default:
+		return 0, ErrNotEncrypted
+	}
+	prefix := make([]byte, cryptNoncePre)
+	if _, err = io.ReadFull(r, prefix); err != nil {
+		return 0, ErrTruncated
+	}
+	header.Write(prefix)
+	aad := header.Bytes()
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return
+	}
+	buf := make([]byte, cryptChunkSize+aead.Overhead())
+	out := make([]byte, 0, cryptChunkSize)
+	for counter := uint32(0); ; counter++ {
+		m, e := io.ReadFull(r, buf)
+		if e == io.EOF {
+			return written, ErrTruncated
+		}
+		if e != nil && e != io.ErrUnexpectedEOF {
+			return written, e
+		}
+		last := e != nil
+		if !last {
+			if _, e = r.Peek(1); e == io.EOF {
+				last = true
+			}
+		}
+		if !last && counter == cryptMaxChunks {
+			return written, ErrDecrypt
+		}
+		out, e = aead.Open(out[:0], chunkNonce(prefix, counter, last), buf[:m], aad)
+		if e != nil {
+			return written, ErrDecrypt
+		}
+		n, e := dst.Write(out)
+		written += int64(n)
+		if e != nil || last {
+			return written, e
+		}
+	}
+}
+
+// CopyTransform 复制文件时对内容进行的转换，如加密、解密
+type CopyTransform func(dst io.Writer, src io.Reader) (written int64, err error)
+
+// EncryptTransform 返回使用 secret 加密的 CopyTransform
+func EncryptTransform(secret Secret) CopyTransform {
+	return func(dst io.Writer, src io.Reader) (int64, error) {
+		return EncryptStream(dst, src, secret)
+	}
+}
+
+// DecryptTransform 返回使用 secret 解密的 CopyTransform
+func DecryptTransform(secret Secret) CopyTransform {
+	return func(dst io.Writer, src io.Reader) (int64, error) {
+		return DecryptStream(dst, src, secret)
+	}
+}
+
+// FileCopyWith 复制文件并经过 transform 转换内容，transform 为nil时直接复制。
+// 先写入目标目录下的临时文件，成功后再重命名覆盖目标文件，因此失败时不会留下不完整的内容，
+// dst 与 src 也可以是同一文件（如原地加密）。dst 已存在时保留其权限和属主，否则权限为0644
+func FileCopyWith(dstName, srcName string, transform CopyTransform) (written int64, err error) {
+	if !IsFile(srcName) {
+		return 0, errors.New("src file does not exist")
+	}
+	if transform == nil {
+		transform = io.Copy
+	}
+
+	src, err := os.Open(srcName)
+	if err != nil {
+		return
+	}
+	defer src.Close()
+	err = replaceFile(dstName, 0644, func(dst *os.File) error {
+		var e error
+		written, e = transform(dst, src)
+		// Windows下无法重命名覆盖已打开的文件
+		src.Close()
+		return e
+	})
+	return
+}
+
+// EncryptFile 加密文件 src 并写入 dst，返回写入的字节数
+func EncryptFile(dst, src string, secret Secret) (int64, error) {
+	return FileCopyWith(dst, src, EncryptTransform(secret))
+}
+
+// DecryptFile 解密文件 src 并写入 dst，返回写入的明文字节数；失败时不会留下 dst
+func DecryptFile(dst, src string, secret Secret) (int64, error) {
+	return FileCopyWith(dst, src, DecryptTransform(secret))
+}
+
+// EncryptString 加密字符串，返回 URL 安全的 base64 文本
+func EncryptString(plain string, secret Secret) (string, error) {
+	var buf bytes.Buffer
+	if _, err := EncryptStream(&buf, bytes.NewBufferString(plain), secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecryptString 解密 EncryptString 的结果
+func DecryptString(text string, secret Secret) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return "", ErrNotEncrypted
+	}
+	var buf bytes.Buffer
+	if _, err = DecryptStream(&buf, bytes.NewReader(raw), secret); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
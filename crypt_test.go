@@ -0,0 +1,189 @@
+package gtc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestPBKDF2(t *testing.T) {
+	k := pbkdf2([]byte("password"), []byte("salt"), 2, 32)
+	if hex.EncodeToString(k) != "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43" {
+		t.Fatal("pbkdf2 error")
+	}
+}
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869 A.1
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	okm := hkdf(ikm, salt, info, 42)
+	if hex.EncodeToString(okm) != "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865" {
+		t.Fatal("hkdf error")
+	}
+}
+
+func TestCryptKeySalt(t *testing.T) {
+	key := make([]byte, 32)
+	var a, b bytes.Buffer
+	EncryptStream(&a, bytes.NewReader([]byte("same")), KeySecret(key))
+	EncryptStream(&b, bytes.NewReader([]byte("same")), KeySecret(key))
+	if a.Bytes()[5] != kdfHKDF || bytes.Equal(a.Bytes()[6:6+cryptSaltSize], b.Bytes()[6:6+cryptSaltSize]) {
+		t.Fatal("key secret should use a random salt per stream")
+	}
+
+	// 兼容没有盐、直接使用原始密钥的旧数据
+	prefix := make([]byte, cryptNoncePre)
+	legacy := append([]byte(cryptMagic), cryptVersion, kdfNone)
+	legacy = append(legacy, prefix...)
+	aead, _ := newGCM(key)
+	legacy = aead.Seal(legacy, chunkNonce(prefix, 0, true), []byte("old"), legacy)
+	var dec bytes.Buffer
+	if _, err := DecryptStream(&dec, bytes.NewReader(legacy), KeySecret(key)); err != nil || dec.String() != "old" {
+		t.Fatalf("legacy decrypt error: %v", err)
+	}
+
+	saved := cryptMaxChunks
+	cryptMaxChunks = 2
+	defer func() { cryptMaxChunks = saved }()
+	if _, err := EncryptStream(ioutil.Discard, bytes.NewReader(make([]byte, 3*cryptChunkSize+1)), KeySecret(key)); err != ErrTooLarge {
+		t.Fatalf("chunk counter overflow should fail: %v", err)
+	}
+	var ok bytes.Buffer
+	if _, err := EncryptStream(&ok, bytes.NewReader(make([]byte, 2*cryptChunkSize+1)), KeySecret(key)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptStream(ioutil.Discard, bytes.NewReader(ok.Bytes()), KeySecret(key)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCryptStream(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	for _, size := range []int{0, 10, cryptChunkSize, 2*cryptChunkSize + 123} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+		var enc bytes.Buffer
+		if _, err := EncryptStream(&enc, bytes.NewReader(plain), KeySecret(key)); err != nil {
+			t.Fatal(err)
+		}
+		var dec bytes.Buffer
+		n, err := DecryptStream(&dec, bytes.NewReader(enc.Bytes()), KeySecret(key))
+		if err != nil || n != int64(size) || !bytes.Equal(dec.Bytes(), plain) {
+			t.Fatalf("round trip error for size %d: %v", size, err)
+		}
+
+		if size == 2*cryptChunkSize+123 {
+			raw := enc.Bytes()
+			tampered := append([]byte(nil), raw...)
+			tampered[len(tampered)/2] ^= 1
+			if _, err = DecryptStream(ioutil.Discard, bytes.NewReader(tampered), KeySecret(key)); err != ErrDecrypt {
+				t.Fatal("tampered data should fail")
+			}
+			// 去掉最后一块
+			cut := raw[:len(raw)-(123+16)]
+			if _, err = DecryptStream(ioutil.Discard, bytes.NewReader(cut), KeySecret(key)); err != ErrDecrypt && err != ErrTruncated {
+				t.Fatalf("truncated data should fail: %v", err)
+			}
+		}
+	}
+
+	if _, err := EncryptStream(ioutil.Discard, bytes.NewReader(nil), KeySecret([]byte("short"))); err != ErrInvalidKey {
+		t.Fatal("invalid key")
+	}
+	if _, err := DecryptStream(ioutil.Discard, bytes.NewReader([]byte("plain text")), KeySecret(key)); err != ErrNotEncrypted {
+		t.Fatal("not encrypted")
+	}
+}
+
+func TestCryptForgedHeader(t *testing.T) {
+	var enc bytes.Buffer
+	if _, err := EncryptStream(&enc, bytes.NewReader([]byte("data")), PasswordSecret("pw")); err != nil {
+		t.Fatal(err)
+	}
+	forged := append([]byte(nil), enc.Bytes()...)
+	copy(forged[6:10], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	done := make(chan error, 1)
+	go func() {
+		_, err := DecryptStream(ioutil.Discard, bytes.NewReader(forged), PasswordSecret("pw"))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != ErrNotEncrypted {
+			t.Fatalf("forged iterations should be rejected: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("forged iterations should not run pbkdf2")
+	}
+}
+
+func TestCryptFile(t *testing.T) {
+	ws, err := TempWorkspace("gtc-crypt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	secret := PasswordSecret("p@ssw0rd")
+	if _, err = EncryptFile(ws.Path("go.mod.enc"), "go.mod", secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = DecryptFile(ws.Path("go.mod"), ws.Path("go.mod.enc"), secret); err != nil {
+		t.Fatal(err)
+	}
+	src, _ := MD5File("go.mod")
+	dst, _ := MD5File(ws.Path("go.mod"))
+	if src != dst {
+		t.Fatal("decrypted file is different")
+	}
+
+	if _, err = DecryptFile(ws.Path("bad"), ws.Path("go.mod.enc"), PasswordSecret("wrong")); err != ErrDecrypt {
+		t.Fatal("wrong password should fail")
+	}
+	if PathExist(ws.Path("bad")) {
+		t.Fatal("failed decrypt should not leave dst")
+	}
+	if _, err = DecryptFile(ws.Path("bad"), ws.Path("go.mod.enc"), KeySecret(make([]byte, 32))); err != ErrSecretMismatch {
+		t.Fatal("secret mismatch")
+	}
+
+	// 原地加密、解密
+	ws.WriteFile("inplace", []byte("plain text"))
+	os.Chmod(ws.Path("inplace"), 0600)
+	if _, err = EncryptFile(ws.Path("inplace"), ws.Path("inplace"), secret); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := FileReadStr(ws.Path("inplace")); s == "plain text" {
+		t.Fatal("in place encrypt error")
+	}
+	if _, err = DecryptFile(ws.Path("inplace"), ws.Path("inplace"), PasswordSecret("wrong")); err != ErrDecrypt {
+		t.Fatal("wrong password should fail")
+	}
+	if _, err = DecryptFile(ws.Path("inplace"), ws.Path("inplace"), secret); err != nil {
+		t.Fatal(err)
+	}
+	stat, _ := os.Stat(ws.Path("inplace"))
+	if s, _ := FileReadStr(ws.Path("inplace")); s != "plain text" || (runtime.GOOS != "windows" && stat.Mode().Perm() != 0600) {
+		t.Fatal("in place decrypt error")
+	}
+	if matches, _ := filepath.Glob(ws.Path(".*tmp*")); len(matches) != 0 {
+		t.Fatalf("temp file left: %v", matches)
+	}
+
+	text, err := EncryptString("你好", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := DecryptString(text, secret); err != nil || s != "你好" {
+		t.Fatal("string round trip error")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"io"
+	"os"
+
+	"tcw.im/gtc/basex"
+)
+
+// Digest 使用 algo 计算 data 的摘要，并以已注册的编码 enc 返回，
+// 如 hex、base58、base62、crockford、z85，可通过 basex.Register 注册新的编码。
+// 摘要长度不满足编码要求时（如 z85 要求4的倍数）返回编码的错误
+func Digest(data []byte, algo HashAlgo, enc string) (string, error) {
+	fn, err := algo.hashFunc()
+	if err != nil {
+		return "", err
+	}
+	h := fn()
+	h.Write(data)
+	return encodeDigest(h.Sum(nil), enc)
+}
+
+// FileDigest 流式计算文件的摘要，并以已注册的编码 enc 返回
+func FileDigest(path string, algo HashAlgo, enc string) (string, error) {
+	fn, err := algo.hashFunc()
+	if err != nil {
+		return "", err
+	}
+	if !IsCommonFile(path) {
+		return "", os.ErrNotExist
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := fn()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return encodeDigest(h.Sum(nil), enc)
+}
+
+func encodeDigest(sum []byte, enc string) (string, error) {
+	e, err := basex.Get(enc)
+	if err != nil {
+		return "", err
+	}
+	return basex.Encode(e, sum)
+}
@@ -0,0 +1,29 @@
+package gtc
+
+import "testing"
+
+func TestDigest(t *testing.T) {
+	data := []byte("hello world!")
+	if d, err := Digest(data, AlgoMD5, "hex"); err != nil || d != MD5("hello world!") {
+		t.Fatal("hex digest should equal MD5")
+	}
+	for _, enc := range []string{"base58", "base62", "crockford", "z85", "base64url"} {
+		if d, err := Digest(data, AlgoSHA256, enc); err != nil || d == "" {
+			t.Fatalf("%s digest error", enc)
+		}
+	}
+	if _, err := Digest(data, AlgoSHA256, "nothing"); err == nil {
+		t.Fatal("unknown encoding should raise error")
+	}
+	if _, err := Digest(data, "crc", "hex"); err != ErrUnsupportedAlgo {
+		t.Fatal("unsupported algo")
+	}
+
+	want, _ := MD5File("go.mod")
+	if d, err := FileDigest("go.mod", AlgoMD5, "hex"); err != nil || d != want {
+		t.Fatal("FileDigest error")
+	}
+	if _, err := FileDigest("not-exist", AlgoMD5, "hex"); err == nil {
+		t.Fatal("FileDigest not exist")
+	}
+}
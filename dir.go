@@ -0,0 +1,153 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"os"
+	"path/filepath"
+)
+
+type dirConfig struct {
+	mode     os.FileMode
+	uid, gid int
+	setgid   bool
+}
+
+// DirOption 是 CreateDirWith、CreateAllDirWith 的选项
+type DirOption func(*dirConfig)
+
+// DirMode 设置目录权限，默认0755。创建后会再调用 chmod，因此不受 umask 影响。
+// 除 os.ModeSetgid 等 Go 的模式位外，也接受 Unix 风格的 02775、01777 等特殊权限位
+func DirMode(mode os.FileMode) DirOption {
+	return func(c *dirConfig) {
+		c.mode = unixMode(mode)
+	}
+}
+
+// DirOwner 设置目录的属主和属组，-1表示不修改，仅支持类 Unix 系统
+func DirOwner(uid, gid int) DirOption {
+	return func(c *dirConfig) {
+		c.uid, c.gid = uid, gid
+	}
+}
+
+// DirSetgid 设置 setgid 位，目录下新建的文件、目录将继承目录的属组，常用于共享目录
+func DirSetgid() DirOption {
+	return func(c *dirConfig) {
+		c.setgid = true
+	}
+}
+
+// unixMode 将 Unix 风格的 setuid、setgid、sticky 位转换为 Go 的模式位
+func unixMode(mode os.FileMode) os.FileMode {
+	perm := mode & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	if mode&04000 != 0 {
+		perm |= os.ModeSetuid
+	}
+	if mode&02000 != 0 {
+		perm |= os.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		perm |= os.ModeSticky
+	}
+	return perm
+}
+
+func newDirConfig(opts []DirOption) *dirConfig {
+	c := &dirConfig{mode: 0755, uid: -1, gid: -1}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.setgid {
+		c.mode |= os.ModeSetgid
+	}
+	return c
+}
+
+// mkdir 创建单个目录并设置权限、属主，目录已存在时返回 false
+func (c *dirConfig) mkdir(path string) (created bool, err error) {
+	if err = os.Mkdir(path, c.mode.Perm()); err != nil {
+		if os.IsExist(err) && IsDir(path) {
+			return false, nil
+		}
+		return false, err
+	}
+	if c.uid != -1 || c.gid != -1 {
+		if err = os.Chown(path, c.uid, c.gid); err != nil {
+			return true, err
+		}
+	}
+	// chown 可能清除特殊权限位，因此最后 chmod
+	return true, os.Chmod(path, c.mode)
+}
+
+// CreateDirWith 创建文件夹（无递归），可设置权限、属主和 setgid 位，返回实际创建的目录。
+// 目录已存在时不做修改，返回空列表
+func CreateDirWith(path string, opts ...DirOption) (created []string, err error) {
+	ok, err := newDirConfig(opts).mkdir(path)
+	if ok {
+		created = append(created, path)
+	}
+	return
+}
+
+// CreateAllDirWith 递归创建文件夹，每个新建的目录都会设置权限、属主和 setgid 位，
+// 已存在的上级目录不做修改。返回按创建顺序排列的目录，出错时同样返回已创建的目录，
+// 调用方可以逆序删除以回滚：
+//
+//	created, err := gtc.CreateAllDirWith(path, gtc.DirMode(02775))
+//	if err != nil {
+//		for i := len(created) - 1; i >= 0; i-- {
+//			os.Remove(created[i])
+//		}
+//	}
+func CreateAllDirWith(path string, opts ...DirOption) (created []string, err error) {
+	c := newDirConfig(opts)
+	path = filepath.Clean(path)
+
+	// 自下而上找到第一个已存在的目录
+	var missing []string
+	for p := path; ; {
+		stat, err := os.Stat(p)
+		if err == nil {
+			if !stat.IsDir() {
+				return nil, &os.PathError{Op: "mkdir", Path: p, Err: ErrNotDir}
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		missing = append(missing, p)
+		parent := filepath.Dir(p)
+		if parent == p {
+			break
+		}
+		p = parent
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		ok, err := c.mkdir(missing[i])
+		if ok {
+			created = append(created, missing[i])
+		}
+		if err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
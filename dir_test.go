@@ -0,0 +1,71 @@
+package gtc
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestCreateDirWith(t *testing.T) {
+	ws, err := TempWorkspace("gtc-dir-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	created, err := CreateDirWith(ws.Path("a"))
+	if err != nil || len(created) != 1 || !IsDir(ws.Path("a")) {
+		t.Fatal("CreateDirWith error")
+	}
+	if created, err = CreateDirWith(ws.Path("a")); err != nil || len(created) != 0 {
+		t.Fatal("CreateDirWith existing dir should do nothing")
+	}
+	if _, err = CreateDirWith(ws.Path("x/y")); err == nil {
+		t.Fatal("CreateDirWith should not create parents")
+	}
+
+	created, err = CreateAllDirWith(ws.Path("a/b/c"), DirMode(0700))
+	if err != nil || len(created) != 2 || created[0] != ws.Path("a/b") || created[1] != ws.Path("a/b/c") {
+		t.Fatalf("CreateAllDirWith error: %v %v", created, err)
+	}
+	if created, err = CreateAllDirWith(ws.Path("a/b/c")); err != nil || len(created) != 0 {
+		t.Fatal("CreateAllDirWith existing dir should do nothing")
+	}
+
+	ws.WriteFile("file", nil)
+	if created, err = CreateAllDirWith(ws.Path("file/sub")); err == nil || len(created) != 0 {
+		t.Fatal("CreateAllDirWith under a file should fail")
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	stat, _ := os.Stat(ws.Path("a/b"))
+	if stat.Mode().Perm() != 0700 {
+		t.Fatalf("mode error: %v", stat.Mode())
+	}
+
+	// 0775 不受 umask 影响，02775 等同于 DirSetgid
+	created, err = CreateAllDirWith(ws.Path("shared/data"), DirMode(02775), DirOwner(os.Getuid(), os.Getgid()))
+	if err != nil || len(created) != 2 {
+		t.Fatal(err)
+	}
+	stat, _ = os.Stat(ws.Path("shared/data"))
+	if stat.Mode().Perm() != 0775 || stat.Mode()&os.ModeSetgid == 0 {
+		t.Fatalf("setgid mode error: %v", stat.Mode())
+	}
+	CreateDirWith(ws.Path("g"), DirMode(0770), DirSetgid())
+	stat, _ = os.Stat(ws.Path("g"))
+	if stat.Mode().Perm() != 0770 || stat.Mode()&os.ModeSetgid == 0 {
+		t.Fatalf("DirSetgid error: %v", stat.Mode())
+	}
+
+	// 无法写入时返回已创建的目录以便回滚
+	if os.Getuid() != 0 {
+		CreateDirWith(ws.Path("ro"), DirMode(0555))
+		created, err = CreateAllDirWith(ws.Path("ro/x/y"))
+		if err == nil || len(created) != 0 {
+			t.Fatal("create in read-only dir should fail")
+		}
+	}
+}
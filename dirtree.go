@@ -0,0 +1,330 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 路径不是目录
+var ErrNotDir = errors.New("not a directory")
+
+// SortKey 目录项排序依据
+type SortKey int
+
+const (
+	// SortByName 按名称（List 中为相对路径）排序
+	SortByName SortKey = iota
+	// SortBySize 按大小排序，相同时按名称
+	SortBySize
+	// SortByModTime 按修改时间排序，相同时按名称
+	SortByModTime
+)
+
+// EntryType 目录项类型过滤
+type EntryType int
+
+const (
+	// AllEntries 所有类型
+	AllEntries EntryType = iota
+	// OnlyFiles 仅普通文件（同 IsCommonFile）
+	OnlyFiles
+	// OnlyDirs 仅目录（同 IsDir）
+	OnlyDirs
+)
+
+// DirFilter 是 Tree、List 共用的过滤选项，零值表示不过滤（隐藏项除外）
+type DirFilter struct {
+	// Include 不为空时仅保留名称匹配任一模式（filepath.Match）的文件，目录不受影响
+	Include []string
+	// Exclude 排除名称匹配任一模式的文件和目录，被排除的目录不再进入
+	Exclude []string
+	// Hidden 为true时包含以 . 开头的隐藏项
+	Hidden bool
+	// MaxDepth 最大深度，root 下的直接子项深度为1，0表示不限制
+	MaxDepth int
+}
+
+// DirEntry 是 List 返回的目录项，符号链接不会被跟随
+type DirEntry struct {
+	// Path 相对于 root 的路径
+	Path    string
+	Name    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+	IsDir   bool
+	// Depth 深度，root 下的直接子项为1
+	Depth int
+	// LinkTarget 符号链接指向的路径，非符号链接为空
+	LinkTarget string
+}
+
+// ListOptions 是 List 的选项
+type ListOptions struct {
+	DirFilter
+	// Recursive 为true时递归列出子目录（受 MaxDepth 限制），否则只列出直接子项
+	Recursive bool
+	Type      EntryType
+	// MinSize、MaxSize 按大小过滤文件，0表示不限制，目录不受影响
+	MinSize int64
+	MaxSize int64
+	// Since、Until 按修改时间过滤，零值表示不限制
+	Since   time.Time
+	Until   time.Time
+	SortBy  SortKey
+	Reverse bool
+}
+
+// TreeOptions 是 Tree 的选项
+type TreeOptions struct {
+	DirFilter
+	// DirsOnly 为true时只显示目录
+	DirsOnly bool
+	// Sizes 为true时在文件名前显示易读的大小（IEC 单位）
+	Sizes   bool
+	SortBy  SortKey
+	Reverse bool
+}
+
+// match 判断名称是否匹配任一模式，无效模式视为不匹配
+func match(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readDirEntries 读取并过滤 dir 的直接子项，rel 是 dir 相对于 root 的路径
+func (f *DirFilter) readDirEntries(dir, rel string, depth int) ([]DirEntry, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]DirEntry, 0, len(infos))
+	for _, fi := range infos {
+		name := fi.Name()
+		if !f.Hidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		if match(name, f.Exclude) {
+			continue
+		}
+		if !fi.IsDir() && len(f.Include) > 0 && !match(name, f.Include) {
+			continue
+		}
+		e := DirEntry{
+			Path:    filepath.Join(rel, name),
+			Name:    name,
+			Size:    fi.Size(),
+			Mode:    fi.Mode(),
+			ModTime: fi.ModTime(),
+			IsDir:   fi.IsDir(),
+			Depth:   depth,
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			e.LinkTarget, _ = os.Readlink(filepath.Join(dir, name))
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// sortEntries 按 key 排序，byPath 为true时按路径而非名称排序
+func sortEntries(entries []DirEntry, key SortKey, reverse, byPath bool) {
+	name := func(e DirEntry) string {
+		if byPath {
+			return e.Path
+		}
+		return e.Name
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if reverse {
+			a, b = b, a
+		}
+		switch key {
+		case SortBySize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case SortByModTime:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+		}
+		return name(a) < name(b)
+	})
+}
+
+// checkRoot 检查 root 是否为目录
+func checkRoot(root string) error {
+	stat, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !stat.IsDir() {
+		return &os.PathError{Op: "open", Path: root, Err: ErrNotDir}
+	}
+	return nil
+}
+
+// List 列出 root 下的目录项，可按名称、类型、大小、修改时间过滤并排序。
+// 子目录无法读取时会跳过并继续，最后返回已列出的目录项和遇到的第一个错误
+func List(root string, opts ListOptions) ([]DirEntry, error) {
+	if err := checkRoot(root); err != nil {
+		return nil, err
+	}
+	maxDepth := opts.MaxDepth
+	if !opts.Recursive {
+		maxDepth = 1
+	}
+	var result []DirEntry
+	var firstErr error
+	var walk func(dir, rel string, depth int)
+	walk = func(dir, rel string, depth int) {
+		entries, err := opts.readDirEntries(dir, rel, depth)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		for _, e := range entries {
+			if opts.keep(e) {
+				result = append(result, e)
+			}
+			if e.IsDir && (maxDepth <= 0 || depth < maxDepth) {
+				walk(filepath.Join(dir, e.Name), e.Path, depth+1)
+			}
+		}
+	}
+	walk(root, "", 1)
+	sortEntries(result, opts.SortBy, opts.Reverse, true)
+	return result, firstErr
+}
+
+// keep 判断目录项是否满足类型、大小、修改时间条件
+func (o *ListOptions) keep(e DirEntry) bool {
+	switch o.Type {
+	case OnlyFiles:
+		if !e.Mode.IsRegular() {
+			return false
+		}
+	case OnlyDirs:
+		if !e.IsDir {
+			return false
+		}
+	}
+	if !e.IsDir {
+		if o.MinSize > 0 && e.Size < o.MinSize {
+			return false
+		}
+		if o.MaxSize > 0 && e.Size > o.MaxSize {
+			return false
+		}
+	}
+	if !o.Since.IsZero() && e.ModTime.Before(o.Since) {
+		return false
+	}
+	if !o.Until.IsZero() && e.ModTime.After(o.Until) {
+		return false
+	}
+	return true
+}
+
+// Tree 以类似 tree 命令的格式渲染目录树，第一行为 root，最后一行为目录和文件的统计，如：
+//
+//	root
+//	├── a
+//	│   └── b.txt
+//	└── c.txt
+//
+//	1 directories, 2 files
+//
+// 符号链接显示为 name -> target 且不会被跟随。无法读取的子目录会标记 [error opening dir] 并继续，
+// 最后返回完整的渲染结果和遇到的第一个错误
+func Tree(root string, opts TreeOptions) (string, error) {
+	if err := checkRoot(root); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(root + "\n")
+	dirs, files := 0, 0
+	var firstErr error
+	var walk func(dir, prefix string, depth int)
+	walk = func(dir, prefix string, depth int) {
+		entries, err := opts.readDirEntries(dir, "", depth)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			b.WriteString(prefix + "└── [error opening dir]\n")
+			return
+		}
+		if opts.DirsOnly {
+			n := 0
+			for _, e := range entries {
+				if e.IsDir {
+					entries[n] = e
+					n++
+				}
+			}
+			entries = entries[:n]
+		}
+		sortEntries(entries, opts.SortBy, opts.Reverse, false)
+		for i, e := range entries {
+			branch, indent := "├── ", "│   "
+			if i == len(entries)-1 {
+				branch, indent = "└── ", "    "
+			}
+			b.WriteString(prefix + branch)
+			if opts.Sizes && !e.IsDir {
+				b.WriteString("[" + FormatBytes(e.Size, IEC) + "]  ")
+			}
+			b.WriteString(e.Name)
+			if e.LinkTarget != "" {
+				b.WriteString(" -> " + e.LinkTarget)
+			}
+			b.WriteString("\n")
+			if !e.IsDir {
+				files++
+				continue
+			}
+			dirs++
+			if opts.MaxDepth <= 0 || depth < opts.MaxDepth {
+				walk(filepath.Join(dir, e.Name), prefix+indent, depth+1)
+			}
+		}
+	}
+	walk(root, "", 1)
+	if opts.DirsOnly {
+		fmt.Fprintf(&b, "\n%d directories\n", dirs)
+	} else {
+		fmt.Fprintf(&b, "\n%d directories, %d files\n", dirs, files)
+	}
+	return b.String(), firstErr
+}
@@ -0,0 +1,123 @@
+package gtc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTreeWorkspace(t *testing.T) *Workspace {
+	ws, err := TempWorkspace("gtc-tree-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.WriteFile("a/b.txt", make([]byte, 1536))
+	ws.WriteFile("a/c/d.go", []byte("package d"))
+	ws.WriteFile("e.txt", []byte("e"))
+	ws.WriteFile(".hidden", []byte("h"))
+	ws.WriteFile("node_modules/x.js", []byte("x"))
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(ws.Path("e.txt"), old, old)
+	return ws
+}
+
+func TestList(t *testing.T) {
+	ws := newTreeWorkspace(t)
+	defer ws.Close()
+
+	entries, err := List(ws.Root(), ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Path != "a" || !entries[0].IsDir || entries[1].Path != "e.txt" {
+		t.Fatalf("List error: %+v", entries)
+	}
+
+	entries, _ = List(ws.Root(), ListOptions{
+		DirFilter: DirFilter{Exclude: []string{"node_modules"}},
+		Recursive: true,
+		Type:      OnlyFiles,
+	})
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = filepath.ToSlash(e.Path)
+	}
+	if strings.Join(paths, ",") != "a/b.txt,a/c/d.go,e.txt" {
+		t.Fatalf("List recursive error: %v", paths)
+	}
+	if entries[1].Depth != 3 {
+		t.Fatal("List depth error")
+	}
+
+	entries, _ = List(ws.Root(), ListOptions{
+		DirFilter: DirFilter{Include: []string{"*.txt", ".*"}, MaxDepth: 2, Hidden: true},
+		Recursive: true,
+		Type:      OnlyFiles,
+		SortBy:    SortBySize,
+		Reverse:   true,
+	})
+	if len(entries) != 3 || entries[0].Name != "b.txt" || entries[2].Name != ".hidden" || entries[0].Size != 1536 {
+		t.Fatalf("List sort by size error: %+v", entries)
+	}
+
+	entries, _ = List(ws.Root(), ListOptions{Type: OnlyFiles, Until: time.Now().Add(-time.Minute)})
+	if len(entries) != 1 || entries[0].Name != "e.txt" {
+		t.Fatal("List mtime filter error")
+	}
+	entries, _ = List(ws.Root(), ListOptions{Recursive: true, MinSize: 1024})
+	for _, e := range entries {
+		if !e.IsDir && e.Name != "b.txt" {
+			t.Fatal("List min size error")
+		}
+	}
+
+	if _, err = List(ws.Path("e.txt"), ListOptions{}); err == nil {
+		t.Fatal("List file should fail")
+	}
+}
+
+func TestTree(t *testing.T) {
+	ws := newTreeWorkspace(t)
+	defer ws.Close()
+
+	out, err := Tree(ws.Root(), TreeOptions{Sizes: true, DirFilter: DirFilter{Exclude: []string{"node_modules"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ws.Root() + `
+├── a
+│   ├── [1.5 KiB]  b.txt
+│   └── c
+│       └── [9 B]  d.go
+└── [1 B]  e.txt
+
+2 directories, 3 files
+`
+	if out != expected {
+		t.Fatalf("Tree error:\n%s", out)
+	}
+
+	out, _ = Tree(ws.Root(), TreeOptions{DirsOnly: true, DirFilter: DirFilter{MaxDepth: 1}})
+	if out != ws.Root()+"\n├── a\n└── node_modules\n\n2 directories\n" {
+		t.Fatalf("Tree dirs only error:\n%s", out)
+	}
+
+	if err = os.Symlink("e.txt", ws.Path("link")); err == nil {
+		out, _ = Tree(ws.Root(), TreeOptions{DirFilter: DirFilter{Include: []string{"link"}, MaxDepth: 1}})
+		if !strings.Contains(out, "link -> e.txt") || strings.Contains(out, "── e.txt") {
+			t.Fatalf("Tree symlink error:\n%s", out)
+		}
+	}
+
+	if os.Getuid() == 0 || filepath.Separator != '/' {
+		return
+	}
+	os.Chmod(ws.Path("a/c"), 0)
+	defer os.Chmod(ws.Path("a/c"), 0755)
+	out, err = Tree(ws.Path("a"), TreeOptions{})
+	if err == nil || !strings.Contains(out, "[error opening dir]") || !strings.HasSuffix(out, "1 directories, 1 files\n") {
+		t.Fatalf("Tree unreadable dir error: %v\n%s", err, out)
+	}
+}
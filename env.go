@@ -0,0 +1,195 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BindEnv 的目标不是结构体指针
+var ErrNotStructPtr = errors.New("target must be a non-nil pointer to struct")
+
+// EnvError 汇总 BindEnv 过程中所有缺失或无效的字段
+type EnvError struct {
+	Errors []error
+}
+
+func (e *EnvError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "bind env: " + strings.Join(msgs, "; ")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// BindEnv 从环境变量填充结构体 cfg（必须是结构体指针），字段通过标签配置：
+//
+// `env:"NAME"` 变量名（会加上 prefix_ 前缀），无此标签时使用大写的字段名，"-" 表示跳过；
+// `env:"NAME,required"` 表示变量必须设置；`default:"value"` 表示未设置时的默认值。
+//
+// 嵌套结构体（或其指针）的变量名前缀为 prefix_NAME。
+// 支持 string、bool（按 IsTrue/IsFalse 解析）、整数、浮点数、
+// time.Duration（按 ParseDuration 解析，支持天、周），
+// 以及元素为上述类型、以逗号分隔的切片和 k:v 形式的 map。
+// 变量值为空视同未设置，所有缺失或无效的字段汇总为一个 *EnvError 返回
+func BindEnv(cfg interface{}, prefix string) error {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotStructPtr
+	}
+	e := &EnvError{}
+	bindEnvStruct(rv.Elem(), prefix, e)
+	if len(e.Errors) > 0 {
+		return e
+	}
+	return nil
+}
+
+func bindEnvStruct(rv reflect.Value, prefix string, e *EnvError) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("env")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		if name == "" {
+			name = strings.ToUpper(field.Name)
+		}
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		fv := rv.Field(i)
+		ft := field.Type
+		if ft.Kind() == reflect.Struct && ft != durationType {
+			bindEnvStruct(fv, name, e)
+			continue
+		}
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				fv.Set(reflect.New(ft.Elem()))
+			}
+			bindEnvStruct(fv.Elem(), name, e)
+			continue
+		}
+
+		val := os.Getenv(name)
+		if val == "" {
+			if StrInSlice("required", strings.Split(opts, ",")) {
+				e.Errors = append(e.Errors, fmt.Errorf("%s is required", name))
+				continue
+			}
+			val = field.Tag.Get("default")
+			if val == "" {
+				continue
+			}
+		}
+		if err := setEnvValue(fv, val); err != nil {
+			e.Errors = append(e.Errors, fmt.Errorf("%s: %v", name, err))
+		}
+	}
+}
+
+// setEnvValue 将字符串 val 按 fv 的类型解析并赋值
+func setEnvValue(fv reflect.Value, val string) error {
+	ft := fv.Type()
+	if ft == durationType {
+		d, err := ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch ft.Kind() {
+	case reflect.String:
+		fv.SetString(val)
+	case reflect.Bool:
+		if IsTrue(val) {
+			fv.SetBool(true)
+		} else if IsFalse(val) {
+			fv.SetBool(false)
+		} else {
+			return fmt.Errorf("invalid bool %q", val)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(val), 0, ft.Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(val), 0, ft.Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(val), ft.Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Slice:
+		items := strings.Split(val, ",")
+		s := reflect.MakeSlice(ft, len(items), len(items))
+		for i, item := range items {
+			if err := setEnvValue(s.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(ft)
+		for _, item := range strings.Split(val, ",") {
+			kv := strings.SplitN(item, ":", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map item %q", item)
+			}
+			k := reflect.New(ft.Key()).Elem()
+			if err := setEnvValue(k, strings.TrimSpace(kv[0])); err != nil {
+				return err
+			}
+			v := reflect.New(ft.Elem()).Elem()
+			if err := setEnvValue(v, strings.TrimSpace(kv[1])); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, v)
+		}
+		fv.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", ft)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package gtc
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBindEnv(t *testing.T) {
+	type db struct {
+		Host string `default:"localhost"`
+		Port int    `env:"PORT" default:"6379"`
+	}
+	type config struct {
+		Debug   bool          `env:"DEBUG"`
+		Workers uint8         `env:"WORKERS" default:"4"`
+		Timeout time.Duration `env:"TIMEOUT" default:"1d3s"`
+		Tags    []string      `env:"TAGS"`
+		Weights map[string]float64
+		Token   string `env:"TOKEN,required"`
+		Skip    string `env:"-"`
+		DB      db
+		Cache   *db `env:"CACHE"`
+	}
+	envs := map[string]string{
+		"APP_DEBUG":      "on",
+		"APP_TAGS":       "a, b,c",
+		"APP_WEIGHTS":    "x:1.5,y:2",
+		"APP_TOKEN":      "secret",
+		"APP_SKIP":       "skip",
+		"APP_DB_HOST":    "db.local",
+		"APP_CACHE_PORT": "6380",
+	}
+	for k, v := range envs {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	var cfg config
+	if err := BindEnv(&cfg, "APP"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Debug || cfg.Workers != 4 || cfg.Timeout != 24*time.Hour+3*time.Second {
+		t.Fatal("bind scalar error")
+	}
+	if len(cfg.Tags) != 3 || cfg.Tags[1] != "b" || cfg.Weights["x"] != 1.5 {
+		t.Fatal("bind slice/map error")
+	}
+	if cfg.Token != "secret" || cfg.Skip != "" {
+		t.Fatal("bind tag option error")
+	}
+	if cfg.DB.Host != "db.local" || cfg.DB.Port != 6379 {
+		t.Fatal("bind nested struct error")
+	}
+	if cfg.Cache == nil || cfg.Cache.Host != "localhost" || cfg.Cache.Port != 6380 {
+		t.Fatal("bind nested pointer error")
+	}
+
+	os.Unsetenv("APP_TOKEN")
+	os.Setenv("APP_DEBUG", "maybe")
+	defer os.Unsetenv("APP_DEBUG")
+	os.Setenv("APP_WORKERS", "1024")
+	defer os.Unsetenv("APP_WORKERS")
+	err := BindEnv(&cfg, "APP")
+	e, ok := err.(*EnvError)
+	if !ok || len(e.Errors) != 3 {
+		t.Fatalf("errors should be aggregated: %v", err)
+	}
+
+	if BindEnv(cfg, "") != ErrNotStructPtr {
+		t.Fatal("non-pointer should raise ErrNotStructPtr")
+	}
+}
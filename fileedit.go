@@ -0,0 +1,224 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ReplaceInFile 逐行将文件中的 old 替换为 new，返回替换次数。
+// 按行流式处理，因此 old 不能跨行；无替换时不会改写文件
+func ReplaceInFile(path, old, new string) (n int, err error) {
+	if old == "" {
+		return 0, nil
+	}
+	_, err = rewriteLines(path, func(line string) ([]string, bool) {
+		c := strings.Count(line, old)
+		if c == 0 {
+			return nil, false
+		}
+		n += c
+		return []string{strings.ReplaceAll(line, old, new)}, true
+	}, nil)
+	return
+}
+
+// ReplaceRegexInFile 逐行将文件中匹配正则 pattern 的内容替换为 repl（支持$1等引用），返回替换次数
+func ReplaceRegexInFile(path, pattern, repl string) (n int, err error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return
+	}
+	_, err = rewriteLines(path, func(line string) ([]string, bool) {
+		c := len(re.FindAllStringIndex(line, -1))
+		if c == 0 {
+			return nil, false
+		}
+		n += c
+		return []string{re.ReplaceAllString(line, repl)}, true
+	}, nil)
+	return
+}
+
+// InsertLineAfter 在最后一个匹配正则 match 的行之后插入 line。
+// 若文件中已有与 line 完全相同的行或没有匹配行则不做修改，返回是否插入
+func InsertLineAfter(path, match, line string) (inserted bool, err error) {
+	re, err := regexp.Compile(match)
+	if err != nil {
+		return
+	}
+	last, exists := -1, false
+	err = scanLines(path, func(i int, l string) {
+		if l == line {
+			exists = true
+		}
+		if re.MatchString(l) {
+			last = i
+		}
+	})
+	if err != nil || exists || last < 0 {
+		return
+	}
+	i := -1
+	_, err = rewriteLines(path, func(l string) ([]string, bool) {
+		i++
+		if i != last {
+			return nil, false
+		}
+		return []string{l, line}, true
+	}, nil)
+	return err == nil, err
+}
+
+// DeleteLines 删除所有使 predicate 返回true的行，返回删除的行数
+func DeleteLines(path string, predicate func(line string) bool) (n int, err error) {
+	return rewriteLines(path, func(line string) ([]string, bool) {
+		if !predicate(line) {
+			return nil, false
+		}
+		n++
+		return []string{}, true
+	}, nil)
+}
+
+// EnsureLine 确保文件中存在与 line 完全相同的行，不存在则追加到文件末尾，返回是否追加。
+// 类似 Ansible 的 lineinfile，可重复执行
+func EnsureLine(path, line string) (added bool, err error) {
+	exists := false
+	err = scanLines(path, func(_ int, l string) {
+		if l == line {
+			exists = true
+		}
+	})
+	if err != nil || exists {
+		return
+	}
+	_, err = rewriteLines(path, func(string) ([]string, bool) {
+		return nil, false
+	}, []string{line})
+	return err == nil, err
+}
+
+// readLine 读取一行，返回不含换行符的内容和行尾换行符（\n、\r\n或空）
+func readLine(r *bufio.Reader) (line, eol string, err error) {
+	line, err = r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2], "\r\n", err
+	}
+	if strings.HasSuffix(line, "\n") {
+		return line[:len(line)-1], "\n", err
+	}
+	return line, "", err
+}
+
+// scanLines 流式遍历文件的每一行（不含换行符），i 从0开始
+func scanLines(path string, fn func(i int, line string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	for i := 0; ; i++ {
+		line, _, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fn(i, line)
+	}
+}
+
+// errUnchanged 表示 rewriteLines 没有修改任何内容，无需替换文件
+var errUnchanged = errors.New("unchanged")
+
+// rewriteLines 流式改写文件：edit 返回某行的替换内容（可为多行或空），
+// tail 是追加到文件末尾的行。先写入同目录的临时文件，有修改时再原子地重命名覆盖原文件。
+// path 是符号链接时改写其最终指向的文件，并保留原文件的权限和属主
+func rewriteLines(path string, edit func(line string) ([]string, bool), tail []string) (changed int, err error) {
+	if real, e := filepath.EvalSymlinks(path); e == nil {
+		path = real
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer src.Close()
+
+	err = replaceFile(path, 0644, func(tmp *os.File) error {
+		r := bufio.NewReader(src)
+		w := bufio.NewWriter(tmp)
+		lastEOL := "\n"
+		for {
+			line, eol, e := readLine(r)
+			if e == io.EOF {
+				break
+			}
+			if e != nil {
+				return e
+			}
+			lastEOL = eol
+			out, ok := edit(line)
+			if !ok {
+				w.WriteString(line + eol)
+				continue
+			}
+			changed++
+			nl := eol
+			if nl == "" {
+				nl = "\n"
+			}
+			for i, l := range out {
+				if i == len(out)-1 {
+					w.WriteString(l + eol)
+				} else {
+					w.WriteString(l + nl)
+				}
+			}
+		}
+		if len(tail) > 0 {
+			if lastEOL == "" {
+				w.WriteString("\n")
+			}
+			for _, l := range tail {
+				w.WriteString(l + "\n")
+			}
+			changed++
+		}
+		if changed == 0 {
+			return errUnchanged
+		}
+		// Windows下无法重命名覆盖已打开的文件
+		src.Close()
+		return w.Flush()
+	})
+	if err == errUnchanged {
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package gtc
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFileEdit(t *testing.T) {
+	ws, err := TempWorkspace("gtc-edit-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	conf := ws.Path("sshd_config")
+	ws.WriteFile("sshd_config", []byte("Port 22\r\n#PermitRootLogin yes yes\nPasswordAuthentication yes"))
+
+	n, err := ReplaceInFile(conf, "yes", "no")
+	if err != nil || n != 3 {
+		t.Fatalf("ReplaceInFile error: %d, %v", n, err)
+	}
+	n, err = ReplaceRegexInFile(conf, `^Port (\d+)$`, "Port 2$1")
+	if err != nil || n != 1 {
+		t.Fatal("ReplaceRegexInFile error")
+	}
+	text, _ := FileReadStr(conf)
+	if text != "Port 222\r\n#PermitRootLogin no no\nPasswordAuthentication no" {
+		t.Fatalf("replace result error: %q", text)
+	}
+
+	ok, err := InsertLineAfter(conf, `^#?PermitRootLogin`, "PermitRootLogin no")
+	if err != nil || !ok {
+		t.Fatal("InsertLineAfter error")
+	}
+	ok, _ = InsertLineAfter(conf, `^#?PermitRootLogin`, "PermitRootLogin no")
+	if ok {
+		t.Fatal("InsertLineAfter should be idempotent")
+	}
+	ok, _ = InsertLineAfter(conf, `^NotFound`, "X")
+	if ok {
+		t.Fatal("InsertLineAfter without match should not insert")
+	}
+
+	ok, err = EnsureLine(conf, "UseDNS no")
+	if err != nil || !ok {
+		t.Fatal("EnsureLine error")
+	}
+	ok, _ = EnsureLine(conf, "UseDNS no")
+	if ok {
+		t.Fatal("EnsureLine should be idempotent")
+	}
+	text, _ = FileReadStr(conf)
+	if text != "Port 222\r\n#PermitRootLogin no no\nPermitRootLogin no\nPasswordAuthentication no\nUseDNS no\n" {
+		t.Fatalf("insert result error: %q", text)
+	}
+
+	n, err = DeleteLines(conf, func(line string) bool {
+		return strings.HasPrefix(line, "#")
+	})
+	if err != nil || n != 1 {
+		t.Fatal("DeleteLines error")
+	}
+	text, _ = FileReadStr(conf)
+	if strings.Contains(text, "#") {
+		t.Fatal("DeleteLines result error")
+	}
+
+	if _, err = ReplaceInFile(ws.Path("not-exist"), "a", "b"); err == nil {
+		t.Fatal("not exist file should raise error")
+	}
+}
+
+func TestFileEditSymlinkAndMeta(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlink and mode bits are not supported")
+	}
+	ws, err := TempWorkspace("gtc-edit-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("avail/site", []byte("listen 80\n"))
+	os.Chmod(ws.Path("avail/site"), 0640|os.ModeSetgid)
+	CreateAllDir(ws.Path("enabled"))
+	if err = os.Symlink("../avail/site", ws.Path("enabled/site")); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getuid() == 0 {
+		os.Chown(ws.Path("avail/site"), 65534, 65534)
+	}
+
+	if n, err := ReplaceInFile(ws.Path("enabled/site"), "80", "8080"); err != nil || n != 1 {
+		t.Fatalf("ReplaceInFile symlink error: %d %v", n, err)
+	}
+	if stat, _ := os.Lstat(ws.Path("enabled/site")); stat.Mode()&os.ModeSymlink == 0 {
+		t.Fatal("symlink should be kept")
+	}
+	if s, _ := FileReadStr(ws.Path("avail/site")); s != "listen 8080\n" {
+		t.Fatal("symlink target should be edited")
+	}
+	stat, _ := os.Stat(ws.Path("avail/site"))
+	if stat.Mode() != 0640|os.ModeSetgid {
+		t.Fatalf("mode should be kept: %v", stat.Mode())
+	}
+	if uid, gid, ok := fileOwner(stat); os.Getuid() == 0 && ok && (uid != 65534 || gid != 65534) {
+		t.Fatal("owner should be kept")
+	}
+}
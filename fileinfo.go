@@ -0,0 +1,147 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sniffLen 检测类型时读取的最大字节数
+const sniffLen = 8192
+
+// FileMeta 是 FileInfo 返回的文件元信息
+type FileMeta struct {
+	Path  string
+	Name  string
+	Size  int64
+	Mode  os.FileMode
+	IsDir bool
+
+	// 以下字段仅在 Linux 下有效，其他系统为零值
+	Uid        uint32
+	Gid        uint32
+	Inode      uint64
+	Nlink      uint64
+	AccessTime time.Time
+	ChangeTime time.Time
+
+	ModTime time.Time
+
+	// MIME 根据文件头部的魔数检测，目录为 inode/directory
+	MIME string
+	// IsText 内容是否为文本
+	IsText bool
+	// IsExecutable 内容是否为可执行文件（ELF、PE、Mach-O 或带 #! 的脚本），与权限位无关
+	IsExecutable bool
+}
+
+// FileInfo 返回文件的元信息，并读取文件头部检测 MIME 类型、是否为文本和可执行文件
+func FileInfo(path string) (*FileMeta, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &FileMeta{
+		Path:    path,
+		Name:    filepath.Base(path),
+		Size:    stat.Size(),
+		Mode:    stat.Mode(),
+		IsDir:   stat.IsDir(),
+		ModTime: stat.ModTime(),
+	}
+	fillSysStat(m, stat)
+	if m.IsDir {
+		m.MIME = "inode/directory"
+		return m, nil
+	}
+	if !stat.Mode().IsRegular() {
+		m.MIME = "application/octet-stream"
+		return m, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+	m.MIME = DetectMIME(head)
+	m.IsText = IsTextContent(head)
+	m.IsExecutable = IsExecutableContent(head)
+	return m, nil
+}
+
+// execMagic 可执行文件的魔数及其 MIME 类型
+var execMagic = []struct {
+	magic []byte
+	mime  string
+}{
+	{[]byte("\x7fELF"), "application/x-executable"},
+	{[]byte("MZ"), "application/vnd.microsoft.portable-executable"},
+	{[]byte{0xFE, 0xED, 0xFA, 0xCE}, "application/x-mach-binary"},
+	{[]byte{0xFE, 0xED, 0xFA, 0xCF}, "application/x-mach-binary"},
+	{[]byte{0xCE, 0xFA, 0xED, 0xFE}, "application/x-mach-binary"},
+	{[]byte{0xCF, 0xFA, 0xED, 0xFE}, "application/x-mach-binary"},
+	{[]byte{0xCA, 0xFE, 0xBA, 0xBE}, "application/x-mach-binary"},
+	{[]byte("#!"), "text/x-shellscript"},
+}
+
+// DetectMIME 根据内容头部检测 MIME 类型，优先识别可执行文件，
+// 其余按 WHATWG 标准（http.DetectContentType）检测，无法识别时返回 application/octet-stream
+func DetectMIME(data []byte) string {
+	for _, e := range execMagic {
+		if bytes.HasPrefix(data, e.magic) {
+			return e.mime
+		}
+	}
+	return http.DetectContentType(data)
+}
+
+// IsExecutableContent 判断内容是否为可执行文件，可用于拒绝伪装成图片等的可执行文件
+func IsExecutableContent(data []byte) bool {
+	for _, e := range execMagic {
+		if bytes.HasPrefix(data, e.magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTextContent 判断内容是否为文本：不含NUL字节，且控制字符不超过5%。
+// 不校验编码，因此 UTF-8 和 GBK 等其他编码的文本都会被识别为文本
+func IsTextContent(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return false
+	}
+	ctrl := 0
+	for _, b := range data {
+		if (b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\b' && b != 0x1b) || b == 0x7f {
+			ctrl++
+		}
+	}
+	return ctrl*20 <= len(data)
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fillSysStat 填充 Linux 下的属主、inode、链接数和访问、变更时间
+func fillSysStat(m *FileMeta, stat os.FileInfo) {
+	st, ok := stat.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	m.Uid = st.Uid
+	m.Gid = st.Gid
+	m.Inode = uint64(st.Ino)
+	m.Nlink = uint64(st.Nlink)
+	m.AccessTime = time.Unix(int64(st.Atim.Sec), int64(st.Atim.Nsec))
+	m.ChangeTime = time.Unix(int64(st.Ctim.Sec), int64(st.Ctim.Nsec))
+}
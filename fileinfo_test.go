@@ -0,0 +1,64 @@
+package gtc
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestFileInfo(t *testing.T) {
+	m, err := FileInfo("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "main.go" || m.Size == 0 || m.IsDir || !m.IsText || m.IsExecutable {
+		t.Fatalf("FileInfo error: %+v", m)
+	}
+	if m.MIME != "text/plain; charset=utf-8" {
+		t.Fatalf("main.go mime error: %s", m.MIME)
+	}
+	if runtime.GOOS == "linux" {
+		if m.Inode == 0 || m.Nlink == 0 || m.AccessTime.IsZero() || m.ChangeTime.IsZero() || int(m.Uid) != os.Getuid() {
+			t.Fatalf("FileInfo linux stat error: %+v", m)
+		}
+	}
+
+	d, err := FileInfo(".")
+	if err != nil || !d.IsDir || d.MIME != "inode/directory" {
+		t.Fatal("FileInfo dir error")
+	}
+	if _, err = FileInfo("not-exist"); err == nil {
+		t.Fatal("FileInfo not exist")
+	}
+
+	ws, err := TempWorkspace("gtc-fileinfo-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("fake.png", []byte("\x7fELF\x02\x01\x01\x00\x00\x00"))
+	ws.WriteFile("real.png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	ws.WriteFile("gbk.txt", []byte{0xc4, 0xe3, 0xba, 0xc3, '\n'})
+	fake, _ := FileInfo(ws.Path("fake.png"))
+	if !fake.IsExecutable || fake.IsText || fake.MIME != "application/x-executable" {
+		t.Fatal("disguised executable should be detected")
+	}
+	real, _ := FileInfo(ws.Path("real.png"))
+	if real.IsExecutable || real.MIME != "image/png" {
+		t.Fatal("png detect error")
+	}
+	gbk, _ := FileInfo(ws.Path("gbk.txt"))
+	if !gbk.IsText {
+		t.Fatal("gbk text should be text")
+	}
+
+	if !IsTextContent([]byte("你好"[:4])) {
+		t.Fatal("truncated utf-8 should be text")
+	}
+	if IsTextContent([]byte{1, 2, 3, 4, 5, 'a'}) {
+		t.Fatal("control bytes should be binary")
+	}
+	if DetectMIME([]byte("#!/bin/sh\n")) != "text/x-shellscript" {
+		t.Fatal("shebang mime error")
+	}
+}
@@ -1,3 +1,3 @@
 module tcw.im/gtc
 
-go 1.13
+go 1.18
@@ -0,0 +1,236 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteStandard 字节单位标准
+type ByteStandard int
+
+const (
+	// IEC 二进制单位，1KiB = 1024B
+	IEC ByteStandard = iota
+	// SI 十进制单位，1KB = 1000B
+	SI
+)
+
+var (
+	iecUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits  = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// FormatBytes 将字节数格式化为易读的形式，如 FormatBytes(1536, IEC) 为 "1.5 KiB"，最多保留两位小数
+func FormatBytes(n int64, std ByteStandard) string {
+	base, units := 1024.0, iecUnits
+	if std == SI {
+		base, units = 1000.0, siUnits
+	}
+	sign := ""
+	v := float64(n)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	i := 0
+	for v >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	if i == 0 {
+		return sign + strconv.FormatInt(int64(v), 10) + " B"
+	}
+	return sign + strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64) + " " + units[i]
+}
+
+// 无效的字节大小
+var ErrInvalidBytes = errors.New("invalid byte size")
+
+// byteMultipliers 单位（小写）对应的字节数。单字母的 k、m、g 等按 1024 计算
+var byteMultipliers = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1e3, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1e6, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1e9, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1e12, "tib": 1 << 40,
+	"p": 1 << 50, "pb": 1e15, "pib": 1 << 50,
+	"e": 1 << 60, "eb": 1e18, "eib": 1 << 60,
+}
+
+// ParseBytes 解析字节大小，如 "1.5GiB"、"10 MB"、"512k"、"1024"，单位不区分大小写。
+// KB、MB 等按1000计算，KiB、MiB 以及单字母的 K、M 等按1024计算
+func ParseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.' || (i == 0 && s[i] == '-')) {
+		i++
+	}
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	mul, ok := byteMultipliers[unit]
+	if !ok || num == "" {
+		return 0, ErrInvalidBytes
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, ErrInvalidBytes
+	}
+	v *= mul
+	if v >= math.MaxInt64 || v <= math.MinInt64 {
+		return 0, ErrInvalidBytes
+	}
+	return int64(v), nil
+}
+
+const (
+	// Day 一天
+	Day = 24 * time.Hour
+	// Week 一周
+	Week = 7 * Day
+)
+
+// FormatDuration 将时间间隔格式化为易读的形式，如 "3d4h"、"1h30m"、"2.5s"，
+// 值为0的单位会被省略，小于1秒时同 time.Duration.String
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d < time.Second {
+		return sign + d.String()
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for _, u := range []struct {
+		unit time.Duration
+		name string
+	}{{Day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if n := d / u.unit; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.name)
+			d -= n * u.unit
+		}
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s")
+	}
+	return b.String()
+}
+
+// ParseDuration 在 time.ParseDuration 的基础上支持 d（天）和 w（周）单位，如 "1w2d"、"1.5d"、"3d4h30m"
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, errors.New("invalid duration " + strconv.Quote(orig))
+	}
+
+	invalid := errors.New("invalid duration " + strconv.Quote(orig))
+	// 以整数累加避免精度损失，负数可以比正数多表示1纳秒
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+	var total uint64
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(s) && !(s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+			j++
+		}
+		num, unit := s[:i], s[i:j]
+		s = s[j:]
+		if num == "" {
+			return 0, invalid
+		}
+		var v uint64
+		switch unit {
+		case "d", "w":
+			u := uint64(Day)
+			if unit == "w" {
+				u = uint64(Week)
+			}
+			n, ok := parseUnits(num, u, limit)
+			if !ok {
+				return 0, invalid
+			}
+			v = n
+		default:
+			d, err := time.ParseDuration(num + unit)
+			if err != nil {
+				return 0, invalid
+			}
+			v = uint64(d)
+		}
+		if v > limit-total {
+			return 0, invalid
+		}
+		total += v
+	}
+	// total 为 1<<63 时转换结果即为 math.MinInt64
+	d := time.Duration(total)
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseUnits 将 num 个 unit 转换为纳秒，num 可以有小数部分（只有小数部分使用浮点数计算），
+// 结果超过 limit 时返回false
+func parseUnits(num string, unit, limit uint64) (uint64, bool) {
+	intPart, fracPart := num, ""
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		intPart, fracPart = num[:i], num[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, false
+	}
+	var n uint64
+	if intPart != "" {
+		v, err := strconv.ParseUint(intPart, 10, 64)
+		if err != nil || v > limit/unit {
+			return 0, false
+		}
+		n = v * unit
+	}
+	if fracPart != "" {
+		f, err := strconv.ParseFloat("0."+fracPart, 64)
+		if err != nil {
+			return 0, false
+		}
+		frac := uint64(f * float64(unit))
+		if frac > limit-n {
+			return 0, false
+		}
+		n += frac
+	}
+	return n, true
+}
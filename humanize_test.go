@@ -0,0 +1,102 @@
+package gtc
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBytes(t *testing.T) {
+	fmts := []struct {
+		n    int64
+		std  ByteStandard
+		want string
+	}{
+		{0, IEC, "0 B"},
+		{1023, IEC, "1023 B"},
+		{1536, IEC, "1.5 KiB"},
+		{1536, SI, "1.54 KB"},
+		{-1 << 30, IEC, "-1 GiB"},
+		{1 << 62, IEC, "4 EiB"},
+	}
+	for _, c := range fmts {
+		if got := FormatBytes(c.n, c.std); got != c.want {
+			t.Fatalf("FormatBytes(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+
+	parses := map[string]int64{
+		"1024":    1024,
+		"1.5GiB":  3 << 29,
+		"10 MB":   10e6,
+		"512k":    512 << 10,
+		" 2 kib ": 2048,
+		"3B":      3,
+	}
+	for _, s := range []string{"2562047h47m16.854775807s", "-2562047h47m16.854775808s", "1h1ns", "-1.5h"} {
+		std, _ := time.ParseDuration(s)
+		if got, err := ParseDuration(s); err != nil || got != std {
+			t.Fatalf("ParseDuration(%q) = %v, time.ParseDuration = %v", s, got, std)
+		}
+	}
+	for s, want := range parses {
+		if got, err := ParseBytes(s); err != nil || got != want {
+			t.Fatalf("ParseBytes(%q) = %d, want %d", s, got, want)
+		}
+	}
+	for _, s := range []string{"", "GB", "1.2.3MB", "10 XB", "99999EiB"} {
+		if _, err := ParseBytes(s); err != ErrInvalidBytes {
+			t.Fatalf("ParseBytes(%q) should fail", s)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	fmts := map[time.Duration]string{
+		0:                                   "0s",
+		500 * time.Millisecond:              "500ms",
+		3*Day + 4*time.Hour:                 "3d4h",
+		90 * time.Minute:                    "1h30m",
+		2500 * time.Millisecond:             "2.5s",
+		-(Week + time.Minute + time.Second): "-7d1m1s",
+	}
+	for d, want := range fmts {
+		if got := FormatDuration(d); got != want {
+			t.Fatalf("FormatDuration(%d) = %q, want %q", d, got, want)
+		}
+	}
+
+	parses := map[string]time.Duration{
+		"1w2d":    9 * Day,
+		"1.5d":    36 * time.Hour,
+		"3d4h30m": 3*Day + 4*time.Hour + 30*time.Minute,
+		"-1h":     -time.Hour,
+		"1m30.5s": 90500 * time.Millisecond,
+		"100ms":   100 * time.Millisecond,
+		"365d1ns": 365*Day + 1,
+		".5w":     84 * time.Hour,
+
+		"2562047h47m16.854775807s":   math.MaxInt64,
+		"-2562047h47m16.854775808s":  math.MinInt64,
+		"106751d23h47m16.854775807s": math.MaxInt64,
+	}
+	for _, s := range []string{"2562047h47m16.854775807s", "-2562047h47m16.854775808s", "1h1ns", "-1.5h"} {
+		std, _ := time.ParseDuration(s)
+		if got, err := ParseDuration(s); err != nil || got != std {
+			t.Fatalf("ParseDuration(%q) = %v, time.ParseDuration = %v", s, got, std)
+		}
+	}
+	for s, want := range parses {
+		if got, err := ParseDuration(s); err != nil || got != want {
+			t.Fatalf("ParseDuration(%q) = %v, want %v", s, got, want)
+		}
+	}
+	for _, s := range []string{"", "d", "1x", "1.2.3d", ".d", "2562047h47m16.854775808s", "106752d", "1w-1d"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Fatalf("ParseDuration(%q) should fail", s)
+		}
+	}
+	if d, _ := ParseDuration(FormatDuration(3*Day + 5*time.Second)); d != 3*Day+5*time.Second {
+		t.Fatal("format/parse round trip error")
+	}
+}
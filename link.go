@@ -0,0 +1,127 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 符号链接存在循环
+var ErrLinkLoop = errors.New("too many levels of symbolic links")
+
+// maxLinkDepth 解析符号链接链的最大层数
+const maxLinkDepth = 255
+
+// Symlink 创建指向 target 的符号链接 link。
+// force 为true时若 link 已存在则替换：先在同目录创建临时链接再重命名覆盖，
+// 因此替换是原子的，不会出现 link 不存在的瞬间，适合切换 current 之类的发布链接。
+// link 是实际目录时无法替换，返回错误
+func Symlink(target, link string, force bool) error {
+	if !force {
+		return os.Symlink(target, link)
+	}
+	return replaceLink(link, func(tmp string) error {
+		return os.Symlink(target, tmp)
+	})
+}
+
+// Hardlink 创建指向文件 target 的硬链接 link，force 为true时以原子的方式替换已存在的 link
+func Hardlink(target, link string, force bool) error {
+	if !force {
+		return os.Link(target, link)
+	}
+	return replaceLink(link, func(tmp string) error {
+		return os.Link(target, tmp)
+	})
+}
+
+// replaceLink 调用 create 在 link 同目录下创建临时链接，再重命名为 link
+func replaceLink(link string, create func(tmp string) error) error {
+	var tmp string
+	for i := 0; ; i++ {
+		suffix, err := RandomString(8, AlphabetAlphaNum)
+		if err != nil {
+			return err
+		}
+		tmp = filepath.Join(filepath.Dir(link), "."+filepath.Base(link)+".tmp"+suffix)
+		err = create(tmp)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) || i >= 10 {
+			return err
+		}
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// ReadLinkChain 逐级解析符号链接，返回从 path 开始直到非符号链接的完整路径链，
+// 相对链接按所在目录解析。存在循环时返回 ErrLinkLoop；
+// 链中某个路径不存在（悬空链接）时返回已解析的路径链和对应的错误
+func ReadLinkChain(path string) ([]string, error) {
+	chain := []string{path}
+	seen := map[string]bool{filepath.Clean(path): true}
+	for {
+		stat, err := os.Lstat(path)
+		if err != nil {
+			return chain, err
+		}
+		if stat.Mode()&os.ModeSymlink == 0 {
+			return chain, nil
+		}
+		target, err := os.Readlink(path)
+		if err != nil {
+			return chain, err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = filepath.Clean(target)
+		chain = append(chain, path)
+		if seen[path] || len(chain) > maxLinkDepth {
+			return chain, ErrLinkLoop
+		}
+		seen[path] = true
+	}
+}
+
+// Touch 与 touch 命令类似：将访问、修改时间设置为 mtime（零值时使用当前时间），
+// 路径不存在时创建空文件（不会创建上级目录）。目录和只读文件同样可以更新时间
+func Touch(path string, mtime time.Time) error {
+	if mtime.IsZero() {
+		mtime = time.Now()
+	}
+	err := os.Chtimes(path, mtime, mtime)
+	if !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(path, mtime, mtime)
+}
@@ -0,0 +1,103 @@
+package gtc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSymlink(t *testing.T) {
+	ws, err := TempWorkspace("gtc-link-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("v1/index.html", []byte("v1"))
+	ws.WriteFile("v2/index.html", []byte("v2"))
+	current := ws.Path("current")
+
+	if err = Symlink("v1", current, false); err != nil {
+		t.Skip("symlink not supported:", err)
+	}
+	if Symlink("v2", current, false) == nil {
+		t.Fatal("symlink without force should fail when link exists")
+	}
+	if err = Symlink("v2", current, true); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := FileReadStr(filepath.Join(current, "index.html")); s != "v2" {
+		t.Fatal("symlink replace error")
+	}
+	if err = Symlink("v1", ws.Path("v2"), true); err == nil {
+		t.Fatal("replace real directory should fail")
+	}
+	matches, _ := filepath.Glob(ws.Path(".*tmp*"))
+	if len(matches) != 0 {
+		t.Fatalf("temp link left: %v", matches)
+	}
+
+	Symlink("current", ws.Path("live"), false)
+	chain, err := ReadLinkChain(ws.Path("live"))
+	if err != nil || len(chain) != 3 || chain[1] != current || chain[2] != ws.Path("v2") {
+		t.Fatalf("ReadLinkChain error: %v %v", chain, err)
+	}
+	Symlink("loop-b", ws.Path("loop-a"), false)
+	Symlink("loop-a", ws.Path("loop-b"), false)
+	if _, err = ReadLinkChain(ws.Path("loop-a")); err != ErrLinkLoop {
+		t.Fatal("link loop should be detected")
+	}
+	Symlink("missing", ws.Path("dangling"), false)
+	chain, err = ReadLinkChain(ws.Path("dangling"))
+	if !os.IsNotExist(err) || len(chain) != 2 {
+		t.Fatal("dangling link error")
+	}
+}
+
+func TestHardlinkTouch(t *testing.T) {
+	ws, err := TempWorkspace("gtc-link-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("a", []byte("a"))
+	ws.WriteFile("b", []byte("b"))
+
+	if err = Hardlink(ws.Path("a"), ws.Path("b"), false); err == nil {
+		t.Fatal("hardlink without force should fail when link exists")
+	}
+	if err = Hardlink(ws.Path("a"), ws.Path("b"), true); err != nil {
+		t.Fatal(err)
+	}
+	ws.WriteFile("a", []byte("changed"))
+	if s, _ := FileReadStr(ws.Path("b")); s != "changed" {
+		t.Fatal("hardlink should share content")
+	}
+
+	mtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err = Touch(ws.Path("new"), mtime); err != nil {
+		t.Fatal(err)
+	}
+	stat, _ := os.Stat(ws.Path("new"))
+	if stat.Size() != 0 || !stat.ModTime().Equal(mtime) {
+		t.Fatal("Touch create error")
+	}
+	Touch(ws.Path("a"), time.Time{})
+	stat, _ = os.Stat(ws.Path("a"))
+	if s, _ := FileReadStr(ws.Path("a")); s != "changed" || time.Since(stat.ModTime()) > time.Minute {
+		t.Fatal("Touch existing file error")
+	}
+	ws.WriteFile("ro", nil)
+	os.Chmod(ws.Path("ro"), 0444)
+	for _, p := range []string{ws.Path("ro"), ws.Root()} {
+		if err = Touch(p, mtime); err != nil {
+			t.Fatal(err)
+		}
+		if stat, _ = os.Stat(p); !stat.ModTime().Equal(mtime) {
+			t.Fatalf("Touch %s error", p)
+		}
+	}
+	if Touch(ws.Path("no/such/dir"), mtime) == nil {
+		t.Fatal("Touch should not create parents")
+	}
+}
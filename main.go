@@ -24,8 +24,6 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
-	"strconv"
-	"strings"
 )
 
 const VERSION = "1.0.0"
@@ -71,7 +69,8 @@ func IsCommonFile(path string) bool {
 	return stat.Mode().IsRegular()
 }
 
-// CreateDir 创建文件夹（无递归），如果已存在则直接返回，否则按照0755权限创建
+// CreateDir 创建文件夹（无递归），如果已存在则直接返回，否则按照0755权限创建。
+// 需要指定权限、属主时请使用 CreateDirWith
 func CreateDir(path string) error {
 	if PathNotExist(path) {
 		return os.Mkdir(path, 0755)
@@ -79,7 +78,7 @@ func CreateDir(path string) error {
 	return nil
 }
 
-// CreateAllDir 递归创建文件夹
+// CreateAllDir 递归创建文件夹，需要指定权限、属主时请使用 CreateAllDirWith
 func CreateAllDir(path string) error {
 	if !IsDir(path) {
 		err := os.MkdirAll(path, 0755)
@@ -142,16 +141,11 @@ func FileCopyN(dstName, srcName string, n int64) (written int64, err error) {
 	return io.CopyN(dst, src, n)
 }
 
-// IsTrue 仅当值为 1、t、T、true、True、TRUE、on 时返回布尔值true，其他（错误）返回false
+// IsTrue 仅当值被 DefaultBoolParser 识别为true时返回布尔值true，
+// 如 1、t、true、on、yes、y、enable、是、开（忽略大小写和首尾空白），其他（无法识别）返回false
 func IsTrue(v string) bool {
-	if strings.ToLower(v) == "on" {
-		return true
-	}
-	b, err := strconv.ParseBool(v)
-	if err != nil {
-		return false
-	}
-	return b
+	b, ok := ParseBool(v)
+	return ok && b
 }
 
 // NotTrue 非 IsTrue 则是false
@@ -160,34 +154,20 @@ func NotTrue(v string) bool {
 	return !IsTrue(v)
 }
 
-// IsFalse 仅当值为 0、f、F、false、False、FALSE、off 时返回布尔值true，其他（错误）返回false
+// IsFalse 仅当值被 DefaultBoolParser 识别为false时返回布尔值true，
+// 如 0、f、false、off、no、n、disable、否、关（忽略大小写和首尾空白），其他（无法识别）返回false
 func IsFalse(v string) bool {
-	if strings.ToLower(v) == "off" {
-		return true
-	}
-	b, err := strconv.ParseBool(v)
-	if err != nil {
-		return false
-	}
-	if b {
-		return false
-	}
-	// b is false
-	return true
+	b, ok := ParseBool(v)
+	return ok && !b
 }
 
-// StrInSlice 判断字符串是否在切片中
-func StrInSlice(val string, slice []string) bool {
-	for _, b := range slice {
-		if b == val {
-			return true
-		}
-	}
-	return false
-}
-
-// InArraySlice 判断值是否在数组或切片中，嵌套类型采用reflect.DeepEqual深度对比结果
+// InArraySlice 判断值是否在数组或切片中，嵌套类型采用reflect.DeepEqual深度对比结果。
+// Go 1.18 及以上版本对常见基础类型的切片不使用反射。
+// 需要所有匹配索引、自定义比较或搜索 map 时请使用 SearchSlice、SearchMap
 func InArraySlice(val interface{}, array interface{}) (exists bool, index int) {
+	if exists, index, ok := inSliceFast(val, array); ok {
+		return exists, index
+	}
 	exists = false
 	index = -1
 	kind := reflect.TypeOf(array).Kind()
@@ -234,24 +214,16 @@ func MD5File(filePath string) (MD5 string, err error) {
 	return hex.EncodeToString(hashInBytes), nil
 }
 
-// FindSlice 在切片中寻找一个元素。如果找到则返回其键，否则将返回-1
-func FindSlice(slice []string, val string) int {
-	for i, item := range slice {
-		if item == val {
-			return i
-		}
-	}
-	return -1
-}
-
-// SubStr 截取字符串，start、end是起始、终点（不包含）索引，超出索引返回空值
+// SubStr 截取字符串，start、end是起始、终点（不包含）索引，超出索引返回空值。
+// 需要负数索引、自动截断或按字节、字素簇截取时请使用 SubString
 func SubStr(str string, start uint, end uint) string {
-	rs := []rune(str)
-	length := len(rs)
-
-	if start > uint(length) || end > uint(length) {
+	const maxInt = uint(^uint(0) >> 1)
+	if start > maxInt || end > maxInt {
 		return ""
 	}
-
-	return string(rs[start:end])
+	s, err := SubString(str, int(start), int(end), SubStrOption{Strict: true})
+	if err != nil {
+		return ""
+	}
+	return s
 }
@@ -287,6 +287,16 @@ func TestString(t *testing.T) {
 		t.Fatal("[]int{1} not in s2")
 	}
 
+	has, index = InArraySlice(int64(3), []int64{1, 2, 3})
+	if has != true || index != 2 {
+		t.Fatal("int64 in InArraySlice")
+	}
+
+	has, _ = InArraySlice(3, []int64{1, 2, 3})
+	if has == true {
+		t.Fatal("3(int) not in []int64, types must be identical")
+	}
+
 	sf := []string{"a", "b"}
 	if FindSlice(sf, "a") != 0 {
 		t.Fatal("err, it should be 0 for FindSlice")
@@ -326,6 +336,13 @@ func TestTool(t *testing.T) {
 	}
 }
 
+func BenchmarkInArraySlice(b *testing.B) {
+	s := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for i := 0; i < b.N; i++ {
+		InArraySlice("g", s)
+	}
+}
+
 func BenchmarkSubStr(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		SubStr("abaghjpiowpoejgre8786awef86wer8962", 3, 15)
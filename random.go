@@ -0,0 +1,219 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"tcw.im/gtc/basex"
+)
+
+// 常用字母表
+const (
+	AlphabetDigits   = "0123456789"
+	AlphabetLower    = "abcdefghijklmnopqrstuvwxyz"
+	AlphabetUpper    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	AlphabetAlphaNum = AlphabetDigits + AlphabetLower + AlphabetUpper
+	// AlphabetReadable 去除了易混淆的 0、O、1、l、I
+	AlphabetReadable = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+var (
+	// 字母表为空
+	ErrInvalidAlphabet = errors.New("invalid alphabet")
+	// 长度为负数
+	ErrInvalidLength = errors.New("invalid length")
+)
+
+// RandomString 使用 crypto/rand 从 alphabet 中随机选取 n 个字符，
+// 采用拒绝采样避免取模偏差，alphabet 可以包含多字节字符
+func RandomString(n int, alphabet string) (string, error) {
+	if n < 0 {
+		return "", ErrInvalidLength
+	}
+	chars := []rune(alphabet)
+	size := len(chars)
+	if size == 0 {
+		return "", ErrInvalidAlphabet
+	}
+	out := make([]rune, 0, n)
+	if size > 256 {
+		max := big.NewInt(int64(size))
+		for len(out) < n {
+			i, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			out = append(out, chars[i.Int64()])
+		}
+		return string(out), nil
+	}
+
+	// 只接受小于 limit 的字节，保证每个字符概率相同
+	limit := 256 - 256%size
+	buf := make([]byte, n+n/4+8)
+	for len(out) < n {
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			out = append(out, chars[int(b)%size])
+			if len(out) == n {
+				break
+			}
+		}
+	}
+	return string(out), nil
+}
+
+// TokenEncoding 随机令牌的文本编码
+type TokenEncoding int
+
+const (
+	// TokenHex 十六进制编码
+	TokenHex TokenEncoding = iota
+	// TokenBase64URL URL安全的 base64 编码（无填充）
+	TokenBase64URL
+	// TokenBase62 base62 编码（0-9a-zA-Z）
+	TokenBase62
+)
+
+// Token 生成 nbytes 字节的安全随机令牌并按 enc 编码，适合用作会话ID、API密钥等
+func Token(nbytes int, enc TokenEncoding) (string, error) {
+	if nbytes < 0 {
+		return "", ErrInvalidLength
+	}
+	b := make([]byte, nbytes)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	switch enc {
+	case TokenBase64URL:
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	case TokenBase62:
+		return basex.Base62.EncodeToString(b), nil
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// UUID 是 RFC 4122/9562 定义的通用唯一识别码
+type UUID [16]byte
+
+// 无效的 UUID 字符串
+var ErrInvalidUUID = errors.New("invalid uuid")
+
+// NewUUIDv4 生成随机的版本4 UUID
+func NewUUIDv4() (u UUID, err error) {
+	if _, err = io.ReadFull(rand.Reader, u[:]); err != nil {
+		return
+	}
+	u.setVersion(4)
+	return
+}
+
+// NewUUIDv7 生成版本7 UUID，前48位为毫秒级Unix时间戳，因此可以按时间排序
+func NewUUIDv7() (u UUID, err error) {
+	if _, err = io.ReadFull(rand.Reader, u[6:]); err != nil {
+		return
+	}
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(u[:6], ts[2:])
+	u.setVersion(7)
+	return
+}
+
+func (u *UUID) setVersion(v byte) {
+	u[6] = (u[6] & 0x0f) | v<<4
+	// RFC 4122 variant
+	u[8] = (u[8] & 0x3f) | 0x80
+}
+
+// ParseUUID 解析 UUID，支持标准格式、无连字符、带花括号和 urn:uuid: 前缀，不区分大小写
+func ParseUUID(s string) (u UUID, err error) {
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, ErrInvalidUUID
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return u, ErrInvalidUUID
+	}
+	if _, err = hex.Decode(u[:], []byte(s)); err != nil {
+		return u, ErrInvalidUUID
+	}
+	return u, nil
+}
+
+// String 返回标准格式 xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Version 返回 UUID 版本号
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time 返回版本7 UUID 中的时间戳，其他版本返回零值
+func (u UUID) Time() time.Time {
+	if u.Version() != 7 {
+		return time.Time{}
+	}
+	var ts [8]byte
+	copy(ts[2:], u[:6])
+	ms := int64(binary.BigEndian.Uint64(ts[:]))
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (u *UUID) UnmarshalText(text []byte) (err error) {
+	*u, err = ParseUUID(string(text))
+	return
+}
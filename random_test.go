@@ -0,0 +1,82 @@
+package gtc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRandom(t *testing.T) {
+	s, err := RandomString(32, AlphabetReadable)
+	if err != nil || len(s) != 32 {
+		t.Fatal("RandomString error")
+	}
+	for _, r := range s {
+		if !strings.ContainsRune(AlphabetReadable, r) {
+			t.Fatal("RandomString char not in alphabet")
+		}
+	}
+	s, _ = RandomString(5, "你好")
+	if len([]rune(s)) != 5 {
+		t.Fatal("RandomString multibyte error")
+	}
+	if _, err = RandomString(5, ""); err != ErrInvalidAlphabet {
+		t.Fatal("empty alphabet should raise error")
+	}
+	if _, err = RandomString(-1, "abc"); err != ErrInvalidLength {
+		t.Fatal("negative length should raise ErrInvalidLength")
+	}
+	if _, err = Token(-1, TokenHex); err != ErrInvalidLength {
+		t.Fatal("negative token length should raise ErrInvalidLength")
+	}
+
+	if tk, _ := Token(16, TokenHex); len(tk) != 32 {
+		t.Fatal("hex token error")
+	}
+	if tk, _ := Token(16, TokenBase64URL); len(tk) != 22 || strings.ContainsAny(tk, "+/=") {
+		t.Fatal("base64url token error")
+	}
+	if tk, _ := Token(16, TokenBase62); tk == "" || strings.Trim(tk, AlphabetAlphaNum) != "" {
+		t.Fatal("base62 token error")
+	}
+}
+
+func TestUUID(t *testing.T) {
+	u4, err := NewUUIDv4()
+	if err != nil || u4.Version() != 4 || (u4[8]&0xc0) != 0x80 {
+		t.Fatal("uuid v4 error")
+	}
+	p, err := ParseUUID(u4.String())
+	if err != nil || p != u4 {
+		t.Fatal("uuid parse error")
+	}
+
+	u7, err := NewUUIDv7()
+	if err != nil || u7.Version() != 7 {
+		t.Fatal("uuid v7 error")
+	}
+	if d := time.Since(u7.Time()); d < 0 || d > time.Minute {
+		t.Fatal("uuid v7 time error")
+	}
+	if !u4.Time().IsZero() {
+		t.Fatal("uuid v4 has no time")
+	}
+
+	s := "123E4567-E89B-12D3-A456-426614174000"
+	for _, v := range []string{s, "{" + s + "}", "urn:uuid:" + s, strings.Replace(s, "-", "", -1)} {
+		u, err := ParseUUID(v)
+		if err != nil || u.String() != strings.ToLower(s) {
+			t.Fatalf("ParseUUID(%q) error", v)
+		}
+	}
+	for _, v := range []string{"", "123e4567e89b12d3a456", "123e4567-e89b-12d3-a456_426614174000", strings.Repeat("z", 32)} {
+		if _, err := ParseUUID(v); err != ErrInvalidUUID {
+			t.Fatalf("ParseUUID(%q) should fail", v)
+		}
+	}
+
+	var u UUID
+	if err = u.UnmarshalText([]byte(s)); err != nil || u.Version() != 1 {
+		t.Fatal("uuid UnmarshalText error")
+	}
+}
@@ -0,0 +1,169 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redigo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+var (
+	// 所有工作节点号均已被占用
+	ErrNoWorkerAvailable = errors.New("no worker id available")
+	// 租约已被其他进程占用或已过期
+	ErrLeaseLost = errors.New("worker lease lost")
+)
+
+// 仅当值与令牌一致时续期
+var renewScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// 仅当值与令牌一致时删除
+var releaseScript = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// WorkerLease 基于Redis的工作节点号租约，申请成功后在后台自动续期，续期失败时通过 Lost 通知。
+// 实现了 snowflake.WorkerLeaser 接口，可用于 snowflake.NewWithLeaser
+type WorkerLease struct {
+	db    *DB
+	name  string
+	ttl   time.Duration
+	token string
+
+	mu   sync.Mutex
+	key  string
+	stop chan struct{}
+	lost chan struct{}
+	err  error
+}
+
+// NewWorkerLease 创建名为 name 的租约，ttl 是租约有效期（进程异常退出后节点号最多在 ttl 后被回收）
+func (c *DB) NewWorkerLease(name string, ttl time.Duration) *WorkerLease {
+	return &WorkerLease{db: c, name: name, ttl: ttl}
+}
+
+// Lease 依次尝试占用 [0, maxWorkers) 中的节点号，返回第一个成功占用的节点号
+func (l *WorkerLease) Lease(maxWorkers int64) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.key != "" {
+		return 0, errors.New("worker id already leased")
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return 0, err
+	}
+	token := hex.EncodeToString(b)
+	ms := l.ttl.Milliseconds()
+
+	for id := int64(0); id < maxWorkers; id++ {
+		key := l.name + ":worker:" + strconv.FormatInt(id, 10)
+		_, err := redis.String(l.db.Do("SET", key, token, "NX", "PX", ms))
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		l.key = l.db.Prefix + key
+		l.token = token
+		l.stop = make(chan struct{})
+		l.lost = make(chan struct{})
+		l.err = nil
+		go l.renew(l.key, token, l.stop, l.lost)
+		return id, nil
+	}
+	return 0, ErrNoWorkerAvailable
+}
+
+// renew 每隔 ttl/3 续期一次，直到 stop 被关闭。
+// 键已不属于本租约，或续期持续失败到下一次续期前租约就会过期时，视为租约丢失并关闭 lost
+func (l *WorkerLease) renew(key, token string, stop, lost chan struct{}) {
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			rc := l.db.pool.Get()
+			n, err := redis.Int(renewScript.Do(rc, key, token, l.ttl.Milliseconds()))
+			rc.Close()
+			if err == nil && n == 1 {
+				last = time.Now()
+				continue
+			}
+			if err == nil {
+				err = ErrLeaseLost
+			} else if time.Since(last)+interval < l.ttl {
+				continue
+			}
+			l.mu.Lock()
+			if l.lost == lost {
+				l.err = err
+			}
+			l.mu.Unlock()
+			close(lost)
+			return
+		}
+	}
+}
+
+// Lost 返回一个在租约丢失时关闭的通道，丢失后节点号可能已被其他进程占用，不应再使用。
+// 未申请时返回nil
+func (l *WorkerLease) Lost() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lost
+}
+
+// Err 返回租约丢失的原因，未丢失时返回nil。
+// 键已不属于本租约时为 ErrLeaseLost，否则为续期时Redis的错误
+func (l *WorkerLease) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Release 停止续期并释放节点号，未申请时直接返回
+func (l *WorkerLease) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.key == "" {
+		return nil
+	}
+	close(l.stop)
+	rc := l.db.pool.Get()
+	defer rc.Close()
+	_, err := releaseScript.Do(rc, l.key, l.token)
+	l.key = ""
+	return err
+}
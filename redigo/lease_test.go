@@ -0,0 +1,59 @@
+package redigo
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWorkerLease(t *testing.T) {
+	getConn(t)
+
+	l1 := c.NewWorkerLease("test-lease", 3*time.Second)
+	l2 := c.NewWorkerLease("test-lease", 3*time.Second)
+	defer l1.Release()
+	defer l2.Release()
+
+	id1, err := l1.Lease(2)
+	raise(t, err)
+	id2, err := l2.Lease(2)
+	raise(t, err)
+	if id1 == id2 {
+		t.Fatal("worker id should be unique")
+	}
+	if _, err = c.NewWorkerLease("test-lease", time.Second).Lease(2); err != ErrNoWorkerAvailable {
+		t.Fatal("all worker ids should be leased")
+	}
+
+	raise(t, l1.Release())
+	l3 := c.NewWorkerLease("test-lease", time.Second)
+	defer l3.Release()
+	id3, err := l3.Lease(2)
+	raise(t, err)
+	if id3 != id1 {
+		t.Fatal("released worker id should be reused")
+	}
+}
+
+func TestWorkerLeaseLost(t *testing.T) {
+	getConn(t)
+
+	l := c.NewWorkerLease("test-lease-lost", 600*time.Millisecond)
+	defer l.Release()
+	if l.Lost() != nil {
+		t.Fatal("lost channel should be nil before lease")
+	}
+	id, err := l.Lease(1)
+	raise(t, err)
+	// 模拟租约过期后被其他进程占用
+	_, err = c.Do("SET", "test-lease-lost:worker:"+strconv.FormatInt(id, 10), "other")
+	raise(t, err)
+	select {
+	case <-l.Lost():
+	case <-time.After(2 * time.Second):
+		t.Fatal("lease lost should be detected")
+	}
+	if l.Err() != ErrLeaseLost {
+		t.Fatal("lease lost error")
+	}
+}
@@ -0,0 +1,237 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// 拒绝删除受保护的路径（空路径、文件系统根目录、$HOME 及其上级目录）
+	ErrProtectedPath = errors.New("refusing to remove protected path")
+	// 拒绝删除 AllowedRoot 之外的路径
+	ErrOutsideRoot = errors.New("refusing to remove path outside allowed root")
+	// 删除过程中路径被替换（如目录被换成符号链接），拒绝继续删除
+	ErrPathChanged = errors.New("path changed during removal")
+)
+
+// RemoveOptions 是 RemoveAll 的选项
+type RemoveOptions struct {
+	// AllowedRoot 不为空时只允许删除该目录及其之内的路径，比较前会解析符号链接
+	AllowedRoot string
+	// DryRun 为true时不做任何修改，只返回将被删除的路径
+	DryRun bool
+	// TrashDir 不为空时将路径整体移动到该回收站目录并记录原路径，之后可用 RestoreTrash 恢复。
+	// 回收站目录须与被删除的路径在同一文件系统上
+	TrashDir string
+}
+
+// RemoveError 汇总 RemoveAll 过程中的所有错误
+type RemoveError struct {
+	Errors []error
+}
+
+func (e *RemoveError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "remove all: " + strings.Join(msgs, "; ")
+}
+
+// RemoveAll 是带保护的 os.RemoveAll：拒绝删除空路径、文件系统根目录、$HOME 及其上级目录，
+// 以及 AllowedRoot 之外的路径。path 本身是符号链接时只删除链接。
+//
+// 删除时自底向上逐个删除，某一项失败后继续删除其他项，最后将所有错误汇总为一个 *RemoveError 返回；
+// 返回值 removed 为已删除（DryRun 时为将被删除、回收站模式时为已移入回收站）的路径。
+// 与 os.RemoveAll 相同，path 不存在时返回 nil
+func RemoveAll(path string, opts RemoveOptions) (removed []string, err error) {
+	abs, err := checkRemovePath(path, opts.AllowedRoot)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Lstat(abs); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if opts.TrashDir != "" {
+		if opts.DryRun {
+			return []string{abs}, nil
+		}
+		if err = moveToTrash(abs, opts.TrashDir); err != nil {
+			return nil, err
+		}
+		return []string{abs}, nil
+	}
+
+	e := &RemoveError{}
+	removeTree(abs, abs, opts.DryRun, &removed, e)
+	if len(e.Errors) > 0 {
+		return removed, e
+	}
+	return removed, nil
+}
+
+// checkRemovePath 返回 path 解析符号链接后的绝对路径，并检查是否允许删除
+func checkRemovePath(path, allowedRoot string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", &os.PathError{Op: "remove", Path: path, Err: ErrProtectedPath}
+	}
+	abs, err := realPath(path)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Dir(abs) == abs {
+		return "", &os.PathError{Op: "remove", Path: path, Err: ErrProtectedPath}
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if home, err = realPath(home); err == nil && isWithin(abs, home) {
+			return "", &os.PathError{Op: "remove", Path: path, Err: ErrProtectedPath}
+		}
+	}
+	if allowedRoot != "" {
+		root, err := realPath(allowedRoot)
+		if err != nil {
+			return "", err
+		}
+		if !isWithin(root, abs) {
+			return "", &os.PathError{Op: "remove", Path: path, Err: ErrOutsideRoot}
+		}
+	}
+	return abs, nil
+}
+
+// realPath 返回绝对路径，并解析上级目录中的符号链接（不解析路径本身）
+func realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	dir, base := filepath.Dir(abs), filepath.Base(abs)
+	if dir == abs {
+		return abs, nil
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = real
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	return filepath.Join(dir, base), nil
+}
+
+// isWithin 判断 path 是否为 root 本身或在 root 之内
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// removeTree 自底向上删除 path，失败时记录错误并继续，返回 path 是否已（或将被）删除。
+// 为防止删除过程中目录被替换为指向 root 之外的符号链接，读取目录时确认打开的就是 Lstat 到的目录，
+// 删除每一项前重新 Lstat 并确认其实际路径仍在 root 之内、且未被替换
+func removeTree(root, path string, dryRun bool, removed *[]string, e *RemoveError) bool {
+	stat, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true
+		}
+		e.Errors = append(e.Errors, err)
+		return false
+	}
+	ok := true
+	if stat.IsDir() {
+		names, err := readDirNamesSame(path, stat)
+		if err != nil {
+			e.Errors = append(e.Errors, err)
+			return false
+		}
+		for _, name := range names {
+			if !removeTree(root, filepath.Join(path, name), dryRun, removed, e) {
+				ok = false
+			}
+		}
+	}
+	// 子项删除失败时目录必然非空，不再尝试删除，以免产生多余的错误
+	if !ok {
+		return false
+	}
+	if err = checkUnchanged(root, path, stat); err != nil {
+		e.Errors = append(e.Errors, err)
+		return false
+	}
+	if !dryRun {
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			e.Errors = append(e.Errors, err)
+			return false
+		}
+	}
+	*removed = append(*removed, path)
+	return true
+}
+
+// checkUnchanged 确认 path 仍是之前 Lstat 到的同一文件，且解析上级符号链接后仍在 root 之内
+func checkUnchanged(root, path string, stat os.FileInfo) error {
+	now, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(stat, now) || now.Mode()&os.ModeType != stat.Mode()&os.ModeType {
+		return &os.PathError{Op: "remove", Path: path, Err: ErrPathChanged}
+	}
+	real, err := realPath(path)
+	if err != nil {
+		return err
+	}
+	if !isWithin(root, real) {
+		return &os.PathError{Op: "remove", Path: path, Err: ErrPathChanged}
+	}
+	return nil
+}
+
+// readDirNamesSame 读取目录的子项，并确认打开的目录与 stat 是同一个（未被替换为符号链接）
+func readDirNamesSame(dir string, stat os.FileInfo) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	opened, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !opened.IsDir() || !os.SameFile(stat, opened) {
+		return nil, &os.PathError{Op: "open", Path: dir, Err: ErrPathChanged}
+	}
+	return f.Readdirnames(-1)
+}
+
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Readdirnames(-1)
+}
@@ -0,0 +1,161 @@
+package gtc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveAllGuard(t *testing.T) {
+	for _, p := range []string{"", " ", "/", string(filepath.Separator)} {
+		if _, err := RemoveAll(p, RemoveOptions{DryRun: true}); err == nil {
+			t.Fatalf("remove %q should be refused", p)
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		_, err = RemoveAll(home, RemoveOptions{DryRun: true})
+		if pe, ok := err.(*os.PathError); !ok || pe.Err != ErrProtectedPath {
+			t.Fatalf("remove home should be refused: %v", err)
+		}
+		if _, err = RemoveAll(filepath.Dir(home), RemoveOptions{DryRun: true}); err == nil {
+			t.Fatal("remove parent of home should be refused")
+		}
+	}
+
+	ws, err := TempWorkspace("gtc-remove-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("root/a", nil)
+	ws.WriteFile("other/b", nil)
+	opts := RemoveOptions{AllowedRoot: ws.Path("root")}
+	for _, p := range []string{ws.Path("other"), ws.Path("root/../other"), ws.Root()} {
+		_, err = RemoveAll(p, opts)
+		if pe, ok := err.(*os.PathError); !ok || pe.Err != ErrOutsideRoot {
+			t.Fatalf("remove %s should be refused: %v", p, err)
+		}
+	}
+	// 通过符号链接逃出 AllowedRoot
+	if os.Symlink(ws.Path("other"), ws.Path("root/escape")) == nil {
+		if _, err = RemoveAll(ws.Path("root/escape/b"), opts); err == nil {
+			t.Fatal("remove through symlink should be refused")
+		}
+		if _, err = RemoveAll(ws.Path("root/escape"), opts); err != nil || !PathExist(ws.Path("other/b")) {
+			t.Fatal("remove symlink should only remove the link")
+		}
+	}
+	if removed, err := RemoveAll(ws.Path("root/none"), opts); err != nil || len(removed) != 0 {
+		t.Fatal("remove not exist should return nil")
+	}
+}
+
+func TestRemoveAll(t *testing.T) {
+	ws, err := TempWorkspace("gtc-remove-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("data/a/1", nil)
+	ws.WriteFile("data/b", nil)
+	target := ws.Path("data")
+
+	removed, err := RemoveAll(target, RemoveOptions{DryRun: true, AllowedRoot: ws.Root()})
+	if err != nil || len(removed) != 4 || removed[3] != target || !PathExist(ws.Path("data/a/1")) {
+		t.Fatalf("dry run error: %v %v", removed, err)
+	}
+	removed, err = RemoveAll(target, RemoveOptions{AllowedRoot: ws.Root()})
+	if err != nil || len(removed) != 4 || PathExist(target) {
+		t.Fatalf("remove error: %v %v", removed, err)
+	}
+
+	if os.Getuid() == 0 || filepath.Separator != '/' {
+		return
+	}
+	// 部分失败时继续删除其他项并汇总错误
+	ws.WriteFile("part/locked/1", nil)
+	ws.WriteFile("part/locked/2", nil)
+	ws.WriteFile("part/free", nil)
+	os.Chmod(ws.Path("part/locked"), 0555)
+	defer os.Chmod(ws.Path("part/locked"), 0755)
+	removed, err = RemoveAll(ws.Path("part"), RemoveOptions{})
+	e, ok := err.(*RemoveError)
+	if !ok || len(e.Errors) != 2 || len(removed) != 1 || PathExist(ws.Path("part/free")) {
+		t.Fatalf("partial failure error: %v %v", removed, err)
+	}
+}
+
+func TestRemoveTreeSwap(t *testing.T) {
+	ws, err := TempWorkspace("gtc-remove-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("data/sub/1", nil)
+	ws.WriteFile("other/keep", nil)
+	root := ws.Path("data")
+	sub := ws.Path("data/sub")
+	stat, _ := os.Lstat(sub)
+
+	// 模拟 Lstat 之后目录被替换为指向 root 之外的符号链接
+	os.RemoveAll(sub)
+	if os.Symlink(ws.Path("other"), sub) != nil {
+		t.Skip("symlink is not supported")
+	}
+	if _, err = readDirNamesSame(sub, stat); err == nil {
+		t.Fatal("swapped dir should not be read")
+	}
+	if err = checkUnchanged(root, sub, stat); err == nil {
+		t.Fatal("swapped dir should not be removed")
+	}
+	linkStat, _ := os.Lstat(sub)
+	if err = checkUnchanged(root, filepath.Join(sub, "keep"), linkStat); err == nil {
+		t.Fatal("path resolved outside root should not be removed")
+	}
+	if !PathExist(ws.Path("other/keep")) {
+		t.Fatal("file outside root should be kept")
+	}
+}
+
+func TestRemoveAllTrash(t *testing.T) {
+	ws, err := TempWorkspace("gtc-remove-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.WriteFile("app/releases/v1/index.html", []byte("v1"))
+	trash := ws.Path("trash")
+	target := ws.Path("app/releases/v1")
+
+	if _, err = RemoveAll(ws.Path("app"), RemoveOptions{TrashDir: ws.Path("app/trash")}); err == nil {
+		t.Fatal("trash inside removed path should fail")
+	}
+	removed, err := RemoveAll(target, RemoveOptions{TrashDir: trash})
+	if err != nil || len(removed) != 1 || PathExist(target) {
+		t.Fatalf("trash error: %v %v", removed, err)
+	}
+	items, err := ListTrash(trash)
+	if err != nil || len(items) != 1 || items[0].OriginalPath != target || items[0].DeletedAt.IsZero() {
+		t.Fatalf("ListTrash error: %+v %v", items, err)
+	}
+
+	ws.WriteFile("app/releases/v1", nil)
+	if _, err = RestoreTrash(trash, items[0].ID); !os.IsExist(err) {
+		t.Fatal("restore over existing path should fail")
+	}
+	os.Remove(target)
+	os.Remove(ws.Path("app/releases"))
+	path, err := RestoreTrash(trash, items[0].ID)
+	if err != nil || path != target {
+		t.Fatal(err)
+	}
+	if s, _ := FileReadStr(filepath.Join(target, "index.html")); s != "v1" {
+		t.Fatal("restore content error")
+	}
+	if items, _ = ListTrash(trash); len(items) != 0 {
+		t.Fatal("restored item should leave trash")
+	}
+	if _, err = RestoreTrash(trash, "../app"); err != ErrTrashNotFound {
+		t.Fatal("invalid trash id")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// replaceFile 以原子的方式替换文件内容：先在目标目录创建临时文件，调用 write 写入，
+// 成功后再重命名覆盖 path，失败时不会改变 path。
+// path 是符号链接时替换其最终指向的文件；path 已存在时保留其权限（含 setuid 等特殊位）和属主，
+// 否则使用权限 perm
+func replaceFile(path string, perm os.FileMode, write func(f *os.File) error) (err error) {
+	if real, e := filepath.EvalSymlinks(path); e == nil {
+		path = real
+	}
+	stat, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(name)
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	mode := perm
+	if stat != nil {
+		mode = stat.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		if uid, gid, ok := fileOwner(stat); ok && (uid != os.Getuid() || gid != os.Getgid()) {
+			if err = tmp.Chown(uid, gid); err != nil {
+				return err
+			}
+		}
+	}
+	// chown 会清除 setuid、setgid 位，因此在其后 chmod
+	if err = tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(name, path)
+}
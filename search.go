@@ -0,0 +1,200 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NotSearchableError 表示传入的容器类型不支持搜索
+type NotSearchableError struct {
+	Kind reflect.Kind
+}
+
+func (e *NotSearchableError) Error() string {
+	return "gtc: cannot search in " + e.Kind.String()
+}
+
+type searchConfig struct {
+	equal func(a, b interface{}) bool
+	path  []string
+	keys  bool
+}
+
+// SearchOption 搜索选项
+type SearchOption func(*searchConfig)
+
+// WithEqual 使用自定义的相等判断函数，a 是容器中的元素，b 是要搜索的值，默认使用 reflect.DeepEqual
+func WithEqual(equal func(a, b interface{}) bool) SearchOption {
+	return func(c *searchConfig) {
+		c.equal = equal
+	}
+}
+
+// WithPath 先按点号分隔的路径取出元素的嵌套值再比较，
+// 路径的每一段可以是结构体字段名、map的键或切片的索引，如 "User.Tags.0"
+func WithPath(path string) SearchOption {
+	return func(c *searchConfig) {
+		if path != "" {
+			c.path = strings.Split(path, ".")
+		}
+	}
+}
+
+// MatchKeys 搜索 map 时比较键而不是值
+func MatchKeys() SearchOption {
+	return func(c *searchConfig) {
+		c.keys = true
+	}
+}
+
+// EqualFold 忽略大小写比较字符串的相等判断函数，非字符串时回退到 reflect.DeepEqual
+func EqualFold(a, b interface{}) bool {
+	sa, ok1 := a.(string)
+	sb, ok2 := b.(string)
+	if ok1 && ok2 {
+		return strings.EqualFold(sa, sb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// FloatTolerance 返回允许误差 eps 的数值相等判断函数，整数、浮点数均按float64比较
+func FloatTolerance(eps float64) func(a, b interface{}) bool {
+	return func(a, b interface{}) bool {
+		fa, ok1 := toFloat(a)
+		fb, ok2 := toFloat(b)
+		if ok1 && ok2 {
+			return math.Abs(fa-fb) <= eps
+		}
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+func newSearchConfig(opts []SearchOption) *searchConfig {
+	c := &searchConfig{equal: reflect.DeepEqual}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// match 按路径取值后比较
+func (c *searchConfig) match(item reflect.Value, val interface{}) bool {
+	v, ok := resolvePath(item, c.path)
+	if !ok {
+		return false
+	}
+	return c.equal(v.Interface(), val)
+}
+
+// resolvePath 依次解析路径，任何一段不存在都返回false
+func resolvePath(v reflect.Value, path []string) (reflect.Value, bool) {
+	for _, seg := range path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := v.Type().FieldByName(seg)
+			if !ok || f.PkgPath != "" {
+				return v, false
+			}
+			v = v.FieldByIndex(f.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, false
+			}
+			v = v.MapIndex(reflect.ValueOf(seg).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return v, false
+			}
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= v.Len() {
+				return v, false
+			}
+			v = v.Index(i)
+		default:
+			return v, false
+		}
+	}
+	return v, v.IsValid() && v.CanInterface()
+}
+
+// SearchSlice 返回数组或切片中所有与 val 匹配的元素索引（升序），没有匹配时返回空切片。
+// array 不是数组或切片时返回 *NotSearchableError
+func SearchSlice(val interface{}, array interface{}, opts ...SearchOption) ([]int, error) {
+	rv := reflect.ValueOf(array)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, &NotSearchableError{rv.Kind()}
+	}
+	c := newSearchConfig(opts)
+	indexes := []int{}
+	for i := 0; i < rv.Len(); i++ {
+		if c.match(rv.Index(i), val) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// SearchMap 返回 map 中所有值（使用 MatchKeys 时为键）与 val 匹配的键，
+// 按键的字符串形式排序以保证结果稳定。m 不是 map 时返回 *NotSearchableError。
+// 注意：channel 无法在不消费元素的情况下遍历，因此不支持搜索
+func SearchMap(val interface{}, m interface{}, opts ...SearchOption) ([]interface{}, error) {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Map {
+		return nil, &NotSearchableError{rv.Kind()}
+	}
+	c := newSearchConfig(opts)
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	found := []interface{}{}
+	for _, k := range keys {
+		item := rv.MapIndex(k)
+		if c.keys {
+			item = k
+		}
+		if c.match(item, val) {
+			found = append(found, k.Interface())
+		}
+	}
+	return found, nil
+}
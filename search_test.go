@@ -0,0 +1,63 @@
+package gtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	idx, err := SearchSlice("a", []string{"a", "b", "A", "a"})
+	if err != nil || !reflect.DeepEqual(idx, []int{0, 3}) {
+		t.Fatal("SearchSlice error")
+	}
+	idx, _ = SearchSlice("a", [3]string{"a", "b", "A"}, WithEqual(EqualFold))
+	if !reflect.DeepEqual(idx, []int{0, 2}) {
+		t.Fatal("SearchSlice EqualFold error")
+	}
+	idx, _ = SearchSlice(0.3, []float64{0.1 + 0.2, 0.31, 1}, WithEqual(FloatTolerance(1e-9)))
+	if !reflect.DeepEqual(idx, []int{0}) {
+		t.Fatal("SearchSlice FloatTolerance error")
+	}
+
+	type user struct {
+		Name string
+		Tags []string
+		Meta map[string]int
+	}
+	users := []*user{
+		{Name: "a", Tags: []string{"x"}, Meta: map[string]int{"age": 1}},
+		nil,
+		{Name: "b", Tags: []string{"y", "x"}, Meta: map[string]int{"age": 2}},
+	}
+	idx, _ = SearchSlice("x", users, WithPath("Tags.0"))
+	if !reflect.DeepEqual(idx, []int{0}) {
+		t.Fatal("SearchSlice path error")
+	}
+	idx, _ = SearchSlice(2, users, WithPath("Meta.age"))
+	if !reflect.DeepEqual(idx, []int{2}) {
+		t.Fatal("SearchSlice map path error")
+	}
+	idx, _ = SearchSlice("a", users, WithPath("Nothing"))
+	if len(idx) != 0 {
+		t.Fatal("SearchSlice invalid path should match nothing")
+	}
+
+	m := map[string]int{"b": 1, "a": 1, "c": 2}
+	keys, err := SearchMap(1, m)
+	if err != nil || !reflect.DeepEqual(keys, []interface{}{"a", "b"}) {
+		t.Fatal("SearchMap error")
+	}
+	keys, _ = SearchMap("C", m, MatchKeys(), WithEqual(EqualFold))
+	if !reflect.DeepEqual(keys, []interface{}{"c"}) {
+		t.Fatal("SearchMap keys error")
+	}
+
+	_, err = SearchSlice("a", "abc")
+	if e, ok := err.(*NotSearchableError); !ok || e.Kind != reflect.String {
+		t.Fatal("SearchSlice should raise NotSearchableError")
+	}
+	_, err = SearchMap("a", make(chan string))
+	if _, ok := err.(*NotSearchableError); !ok {
+		t.Fatal("SearchMap should raise NotSearchableError")
+	}
+}
@@ -0,0 +1,210 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package set 基于泛型的集合类型，需要 Go 1.18 及以上版本
+package set
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Ordered 可以直接比较大小的类型
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Set 集合，非并发安全，并发场景请使用 SyncSet
+type Set[T comparable] map[T]struct{}
+
+// New 创建集合并加入 items
+func New[T comparable](items ...T) Set[T] {
+	return FromSlice(items)
+}
+
+// FromSlice 由切片创建集合，如 set.FromSlice([]string{"a", "b"})
+func FromSlice[T comparable](items []T) Set[T] {
+	s := make(Set[T], len(items))
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+	return s
+}
+
+// Add 加入一个或多个元素
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove 移除一个或多个元素，不存在的元素会被忽略
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Has 判断元素是否在集合中
+func (s Set[T]) Has(item T) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Len 返回元素个数
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Clone 返回集合的副本
+func (s Set[T]) Clone() Set[T] {
+	out := make(Set[T], len(s))
+	for item := range s {
+		out[item] = struct{}{}
+	}
+	return out
+}
+
+// Items 以切片返回所有元素，顺序不固定
+func (s Set[T]) Items() []T {
+	out := make([]T, 0, len(s))
+	for item := range s {
+		out = append(out, item)
+	}
+	return out
+}
+
+// Sorted 按 less 排序后返回所有元素
+func (s Set[T]) Sorted(less func(a, b T) bool) []T {
+	out := s.Items()
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// Each 按 Sorted 的顺序遍历，f 返回false时停止
+func (s Set[T]) Each(less func(a, b T) bool, f func(item T) bool) {
+	for _, item := range s.Sorted(less) {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// SortedItems 返回按升序排列的所有元素
+func SortedItems[T Ordered](s Set[T]) []T {
+	return s.Sorted(func(a, b T) bool { return a < b })
+}
+
+// Union 并集
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	out := s.Clone()
+	for item := range other {
+		out[item] = struct{}{}
+	}
+	return out
+}
+
+// Intersection 交集
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	small, big := s, other
+	if len(small) > len(big) {
+		small, big = big, small
+	}
+	out := make(Set[T])
+	for item := range small {
+		if big.Has(item) {
+			out[item] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Difference 差集，即在 s 中但不在 other 中的元素
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	out := make(Set[T])
+	for item := range s {
+		if !other.Has(item) {
+			out[item] = struct{}{}
+		}
+	}
+	return out
+}
+
+// SymmetricDifference 对称差集，即只在其中一个集合中的元素
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	out := s.Difference(other)
+	for item := range other {
+		if !s.Has(item) {
+			out[item] = struct{}{}
+		}
+	}
+	return out
+}
+
+// IsSubset 判断 s 是否为 other 的子集
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for item := range s {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset 判断 s 是否为 other 的超集
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal 判断两个集合元素是否相同
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// MarshalJSON 序列化为 JSON 数组，元素按其 JSON 文本排序以保证结果稳定
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(s))
+	for item := range s {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, raw)
+	}
+	sort.Slice(items, func(i, j int) bool { return string(items[i]) < string(items[j]) })
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON 从 JSON 数组反序列化，会加入到已有元素中
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if *s == nil {
+		*s = make(Set[T], len(items))
+	}
+	s.Add(items...)
+	return nil
+}
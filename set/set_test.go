@@ -0,0 +1,83 @@
+//go:build go1.18
+// +build go1.18
+
+package set
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := FromSlice([]string{"1", "", "a"})
+	b := New("a", "b")
+	if !a.Has("") || a.Has("b") || a.Len() != 3 {
+		t.Fatal("Has error")
+	}
+	if !reflect.DeepEqual(SortedItems(a.Union(b)), []string{"", "1", "a", "b"}) {
+		t.Fatal("Union error")
+	}
+	if !reflect.DeepEqual(SortedItems(a.Intersection(b)), []string{"a"}) {
+		t.Fatal("Intersection error")
+	}
+	if !reflect.DeepEqual(SortedItems(a.Difference(b)), []string{"", "1"}) {
+		t.Fatal("Difference error")
+	}
+	if !reflect.DeepEqual(SortedItems(a.SymmetricDifference(b)), []string{"", "1", "b"}) {
+		t.Fatal("SymmetricDifference error")
+	}
+	if !New("a").IsSubset(b) || b.IsSubset(a) || !b.IsSuperset(New("b")) {
+		t.Fatal("IsSubset error")
+	}
+	a.Remove("", "1")
+	a.Add("c")
+	if !a.Equal(New("a", "c")) {
+		t.Fatal("Add/Remove error")
+	}
+
+	var got []int
+	New(3, 1, 2).Each(func(x, y int) bool { return x > y }, func(v int) bool {
+		got = append(got, v)
+		return v != 2
+	})
+	if !reflect.DeepEqual(got, []int{3, 2}) {
+		t.Fatal("Each error")
+	}
+
+	raw, err := json.Marshal(New(3, 1, 2))
+	if err != nil || string(raw) != "[1,2,3]" {
+		t.Fatalf("MarshalJSON error: %s", raw)
+	}
+	var s Set[int]
+	if err = json.Unmarshal(raw, &s); err != nil || !s.Equal(New(1, 2, 3)) {
+		t.Fatal("UnmarshalJSON error")
+	}
+}
+
+func TestSyncSet(t *testing.T) {
+	s := NewSync[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i, i+1)
+			s.Has(i)
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 11 || !s.IsSubset(s.Snapshot()) {
+		t.Fatal("SyncSet error")
+	}
+	s.Remove(0)
+	if s.Intersection(New(0, 1)).Len() != 1 {
+		t.Fatal("SyncSet Intersection error")
+	}
+	raw, _ := json.Marshal(s)
+	c := NewSync[int]()
+	if json.Unmarshal(raw, c) != nil || c.Len() != 10 {
+		t.Fatal("SyncSet json error")
+	}
+}
@@ -0,0 +1,127 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package set
+
+import "sync"
+
+// SyncSet 并发安全的集合，零值可直接使用，集合运算返回普通的 Set 快照
+type SyncSet[T comparable] struct {
+	mu sync.RWMutex
+	s  Set[T]
+}
+
+// NewSync 创建并发安全的集合并加入 items
+func NewSync[T comparable](items ...T) *SyncSet[T] {
+	return &SyncSet[T]{s: FromSlice(items)}
+}
+
+// Add 加入一个或多个元素
+func (c *SyncSet[T]) Add(items ...T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.s == nil {
+		c.s = make(Set[T], len(items))
+	}
+	c.s.Add(items...)
+}
+
+// Remove 移除一个或多个元素
+func (c *SyncSet[T]) Remove(items ...T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.s.Remove(items...)
+}
+
+// Has 判断元素是否在集合中
+func (c *SyncSet[T]) Has(item T) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.Has(item)
+}
+
+// Len 返回元素个数
+func (c *SyncSet[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.s)
+}
+
+// Snapshot 返回当前元素的副本
+func (c *SyncSet[T]) Snapshot() Set[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.Clone()
+}
+
+// Union 并集
+func (c *SyncSet[T]) Union(other Set[T]) Set[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.Union(other)
+}
+
+// Intersection 交集
+func (c *SyncSet[T]) Intersection(other Set[T]) Set[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.Intersection(other)
+}
+
+// Difference 差集
+func (c *SyncSet[T]) Difference(other Set[T]) Set[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.Difference(other)
+}
+
+// SymmetricDifference 对称差集
+func (c *SyncSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.SymmetricDifference(other)
+}
+
+// IsSubset 判断是否为 other 的子集
+func (c *SyncSet[T]) IsSubset(other Set[T]) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.IsSubset(other)
+}
+
+// Sorted 按 less 排序后返回所有元素
+func (c *SyncSet[T]) Sorted(less func(a, b T) bool) []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.Sorted(less)
+}
+
+// MarshalJSON 序列化为 JSON 数组
+func (c *SyncSet[T]) MarshalJSON() ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.MarshalJSON()
+}
+
+// UnmarshalJSON 从 JSON 数组反序列化
+func (c *SyncSet[T]) UnmarshalJSON(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.s.UnmarshalJSON(data)
+}
@@ -0,0 +1,284 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HashAlgo 哈希算法名称
+type HashAlgo string
+
+// 支持的哈希算法
+const (
+	AlgoMD5    HashAlgo = "md5"
+	AlgoSHA1   HashAlgo = "sha1"
+	AlgoSHA256 HashAlgo = "sha256"
+	AlgoSHA512 HashAlgo = "sha512"
+)
+
+// 不支持的哈希算法
+var ErrUnsupportedAlgo = errors.New("unsupported hash algorithm")
+
+// hashFunc 返回算法对应的构造函数
+func (a HashAlgo) hashFunc() (func() hash.Hash, error) {
+	switch a {
+	case AlgoMD5:
+		return md5.New, nil
+	case AlgoSHA1:
+		return sha1.New, nil
+	case AlgoSHA256:
+		return sha256.New, nil
+	case AlgoSHA512:
+		return sha512.New, nil
+	}
+	return nil, ErrUnsupportedAlgo
+}
+
+// HMACSign 使用 key 和算法 algo 计算 msg 的HMAC，返回十六进制字符串
+func HMACSign(key, msg []byte, algo HashAlgo) (string, error) {
+	sum, err := hmacSum(key, msg, algo)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// HMACVerify 以恒定时间比较 signature（HMACSign 的结果）是否正确，防止时序攻击
+func HMACVerify(key, msg []byte, signature string, algo HashAlgo) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	sum, err := hmacSum(key, msg, algo)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(sum, sig)
+}
+
+func hmacSum(key, msg []byte, algo HashAlgo) ([]byte, error) {
+	fn, err := algo.hashFunc()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(fn, key)
+	mac.Write(msg)
+	return mac.Sum(nil), nil
+}
+
+// 签名令牌相关错误
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrBadSignature   = errors.New("bad signature")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrUnknownKey     = errors.New("unknown signing key")
+	ErrInvalidKeyID   = errors.New("key id must be non-empty and contain only [A-Za-z0-9_-]")
+	ErrNoSigningKey   = errors.New("no signing key, call Rotate first")
+)
+
+// 令牌格式版本，参与签名
+const tokenVersion = "v1"
+
+// Signer 生成和校验带过期时间的签名令牌（类似 itsdangerous 的 URLSafeTimedSerializer），并发安全。
+//
+// 令牌格式为 base64url(payload).过期时间(36进制Unix秒，0表示永不过期).密钥ID.base64url(签名)，
+// 全部字符都是URL安全的。密钥ID随令牌携带，因此轮换密钥后旧令牌在旧密钥被移除前仍可校验
+type Signer struct {
+	// Algo 签名算法，默认 AlgoSHA256。开始签名后修改请使用 SetAlgo
+	Algo HashAlgo
+
+	mu      sync.RWMutex
+	keys    map[string][]byte
+	current string
+}
+
+// NewSigner 创建使用密钥 key（ID为kid，只能由字母、数字、下划线和连字符组成）签名的 Signer
+func NewSigner(kid string, key []byte) (*Signer, error) {
+	s := &Signer{Algo: AlgoSHA256}
+	if err := s.Rotate(kid, key); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validKeyID 密钥ID随令牌携带，只允许URL安全且不含分隔符的字符 [A-Za-z0-9_-]
+func validKeyID(kid string) bool {
+	if kid == "" {
+		return false
+	}
+	for i := 0; i < len(kid); i++ {
+		c := kid[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// addKey 校验密钥ID并添加密钥，调用方需持有写锁
+func (s *Signer) addKey(kid string, key []byte) error {
+	if !validKeyID(kid) {
+		return ErrInvalidKeyID
+	}
+	if s.keys == nil {
+		s.keys = make(map[string][]byte)
+	}
+	s.keys[kid] = key
+	return nil
+}
+
+// AddKey 添加仅用于校验的密钥，如轮换前的旧密钥
+func (s *Signer) AddKey(kid string, key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addKey(kid, key)
+}
+
+// Rotate 添加密钥并将其作为新的签名密钥，原密钥仍保留用于校验。
+// 零值的 Signer 需先调用 Rotate 才能签名
+func (s *Signer) Rotate(kid string, key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.addKey(kid, key); err != nil {
+		return err
+	}
+	s.current = kid
+	return nil
+}
+
+// RemoveKey 移除密钥，此后用该密钥签名的令牌将校验失败；不能移除当前签名密钥
+func (s *Signer) RemoveKey(kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if kid != s.current {
+		delete(s.keys, kid)
+	}
+}
+
+// SetAlgo 并发安全地修改签名算法，修改后用原算法签名的令牌将校验失败
+func (s *Signer) SetAlgo(algo HashAlgo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Algo = algo
+}
+
+// algo 返回签名算法，调用方需持有读锁
+func (s *Signer) algo() HashAlgo {
+	if s.Algo == "" {
+		return AlgoSHA256
+	}
+	return s.Algo
+}
+
+func signature(key []byte, body string, algo HashAlgo) ([]byte, error) {
+	return hmacSum(key, []byte(tokenVersion+"."+body), algo)
+}
+
+// Sign 对 payload 签名，过期时间精确到秒，ttl 为0时永不过期
+func (s *Signer) Sign(payload []byte, ttl time.Duration) (string, error) {
+	s.mu.RLock()
+	kid := s.current
+	key, ok := s.keys[kid]
+	algo := s.algo()
+	s.mu.RUnlock()
+	if !ok {
+		return "", ErrNoSigningKey
+	}
+
+	var exp int64
+	if ttl > 0 {
+		exp = time.Now().Add(ttl).Unix()
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload) + "." + strconv.FormatInt(exp, 36) + "." + kid
+	sig, err := signature(key, body, algo)
+	if err != nil {
+		return "", err
+	}
+	return body + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Verify 校验令牌的签名和有效期，返回 payload
+func (s *Signer) Verify(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return nil, ErrMalformedToken
+	}
+	s.mu.RLock()
+	key, ok := s.keys[parts[2]]
+	algo := s.algo()
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	want, err := signature(key, strings.Join(parts[:3], "."), algo)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(sig, want) {
+		return nil, ErrBadSignature
+	}
+
+	exp, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if exp > 0 && time.Now().Unix() >= exp {
+		return nil, ErrTokenExpired
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	return payload, nil
+}
+
+// SignJSON 将 v 序列化为JSON后签名
+func (s *Signer) SignJSON(v interface{}, ttl time.Duration) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return s.Sign(raw, ttl)
+}
+
+// VerifyJSON 校验令牌并将 payload 反序列化到 v
+func (s *Signer) VerifyJSON(token string, v interface{}) error {
+	raw, err := s.Verify(token)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
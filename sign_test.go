@@ -0,0 +1,101 @@
+package gtc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMAC(t *testing.T) {
+	key, msg := []byte("key"), []byte("The quick brown fox jumps over the lazy dog")
+	sig, err := HMACSign(key, msg, AlgoSHA256)
+	if err != nil || sig != "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8" {
+		t.Fatal("HMACSign sha256 error")
+	}
+	if !HMACVerify(key, msg, sig, AlgoSHA256) {
+		t.Fatal("HMACVerify error")
+	}
+	if HMACVerify([]byte("other"), msg, sig, AlgoSHA256) || HMACVerify(key, msg, "zz", AlgoSHA256) {
+		t.Fatal("HMACVerify should fail")
+	}
+	if _, err = HMACSign(key, msg, "crc32"); err != ErrUnsupportedAlgo {
+		t.Fatal("unsupported algo")
+	}
+}
+
+func TestSignerKeys(t *testing.T) {
+	var zero Signer
+	if _, err := zero.Sign([]byte("x"), 0); err != ErrNoSigningKey {
+		t.Fatal("zero Signer should not sign without key")
+	}
+	if err := zero.Rotate("k1", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := zero.Sign([]byte("x"), 0); err != nil || !strings.Contains(token, ".k1.") {
+		t.Fatal("zero Signer should sign after Rotate")
+	}
+	for _, kid := range []string{"", "a.b", "a/b", "a b", "a%2E", "键"} {
+		if _, err := NewSigner(kid, []byte("k")); err != ErrInvalidKeyID {
+			t.Fatalf("kid %q should be invalid", kid)
+		}
+		if zero.AddKey(kid, []byte("k")) != ErrInvalidKeyID || zero.Rotate(kid, []byte("k")) != ErrInvalidKeyID {
+			t.Fatalf("kid %q should be invalid", kid)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	s, err := NewSigner("k1", []byte("secret1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := s.SignJSON(map[string]string{"file": "a.zip"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]string
+	if err = s.VerifyJSON(token, &v); err != nil || v["file"] != "a.zip" {
+		t.Fatal("VerifyJSON error")
+	}
+
+	if err = s.Rotate("k2", []byte("secret2")); err != nil {
+		t.Fatal(err)
+	}
+	token2, _ := s.Sign([]byte("new"), 0)
+	if !strings.Contains(token2, ".k2.") {
+		t.Fatal("rotate should sign with new key")
+	}
+	if _, err = s.Verify(token); err != nil {
+		t.Fatal("old token should still be valid after rotation")
+	}
+	s.RemoveKey("k1")
+	if _, err = s.Verify(token); err != ErrUnknownKey {
+		t.Fatal("removed key should be unknown")
+	}
+	if p, err := s.Verify(token2); err != nil || string(p) != "new" {
+		t.Fatal("never-expiring token error")
+	}
+
+	parts := strings.Split(token2, ".")
+	parts[0] = "dGFtcGVyZWQ"
+	if _, err = s.Verify(strings.Join(parts, ".")); err != ErrBadSignature {
+		t.Fatal("tampered token should fail")
+	}
+	if _, err = s.Verify("a.b.c"); err != ErrMalformedToken {
+		t.Fatal("malformed token")
+	}
+
+	s.SetAlgo(AlgoSHA512)
+	if _, err = s.Verify(token2); err != ErrBadSignature {
+		t.Fatal("token signed with other algo should fail")
+	}
+	token3, _ := s.Sign([]byte("x"), 0)
+	if _, err = s.Verify(token3); err != nil {
+		t.Fatal("sign with SetAlgo error")
+	}
+
+	expired, _ := s.Sign([]byte("x"), time.Nanosecond)
+	if _, err = s.Verify(expired); err != ErrTokenExpired {
+		t.Fatal("token should expire")
+	}
+}
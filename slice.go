@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import "tcw.im/gtc/sliceutil"
+
+// StrInSlice 判断字符串是否在切片中
+func StrInSlice(val string, slice []string) bool {
+	return sliceutil.Contains(slice, val)
+}
+
+// FindSlice 在切片中寻找一个元素。如果找到则返回其键，否则将返回-1
+func FindSlice(slice []string, val string) int {
+	return sliceutil.Index(slice, val)
+}
+
+// inSliceFast 对常见基础类型的切片直接比较，ok 为false表示需要回退到反射
+func inSliceFast(val interface{}, array interface{}) (exists bool, index int, ok bool) {
+	switch s := array.(type) {
+	case []string:
+		exists, index = indexOf(s, val)
+	case []int:
+		exists, index = indexOf(s, val)
+	case []int64:
+		exists, index = indexOf(s, val)
+	case []int32:
+		exists, index = indexOf(s, val)
+	case []uint:
+		exists, index = indexOf(s, val)
+	case []uint64:
+		exists, index = indexOf(s, val)
+	case []uint32:
+		exists, index = indexOf(s, val)
+	case []float64:
+		exists, index = indexOf(s, val)
+	case []bool:
+		exists, index = indexOf(s, val)
+	case []byte:
+		exists, index = indexOf(s, val)
+	default:
+		return false, -1, false
+	}
+	return exists, index, true
+}
+
+func indexOf[T comparable](s []T, val interface{}) (bool, int) {
+	v, ok := val.(T)
+	if !ok {
+		return false, -1
+	}
+	i := sliceutil.Index(s, v)
+	return i >= 0, i
+}
@@ -0,0 +1,40 @@
+//go:build !go1.18
+// +build !go1.18
+
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+// StrInSlice 判断字符串是否在切片中
+func StrInSlice(val string, slice []string) bool {
+	return FindSlice(slice, val) >= 0
+}
+
+// FindSlice 在切片中寻找一个元素。如果找到则返回其键，否则将返回-1
+func FindSlice(slice []string, val string) int {
+	for i, item := range slice {
+		if item == val {
+			return i
+		}
+	}
+	return -1
+}
+
+// inSliceFast Go 1.18 以下版本不支持泛型，总是回退到反射
+func inSliceFast(val interface{}, array interface{}) (exists bool, index int, ok bool) {
+	return false, -1, false
+}
@@ -0,0 +1,158 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package sliceutil 基于泛型的切片工具函数，需要 Go 1.18 及以上版本
+package sliceutil
+
+// Contains 判断 v 是否在切片中
+func Contains[T comparable](s []T, v T) bool {
+	return Index(s, v) >= 0
+}
+
+// Index 返回 v 在切片中第一次出现的索引，不存在返回-1
+func Index[T comparable](s []T, v T) int {
+	for i, item := range s {
+		if item == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexFunc 返回第一个使 f 返回true的元素索引，不存在返回-1
+func IndexFunc[T any](s []T, f func(T) bool) int {
+	for i, item := range s {
+		if f(item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Filter 返回所有使 keep 返回true的元素组成的新切片
+func Filter[T any](s []T, keep func(T) bool) []T {
+	out := make([]T, 0, len(s))
+	for _, item := range s {
+		if keep(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Map 返回对每个元素执行 f 后的结果组成的新切片
+func Map[T, R any](s []T, f func(T) R) []R {
+	out := make([]R, len(s))
+	for i, item := range s {
+		out[i] = f(item)
+	}
+	return out
+}
+
+// Reduce 从 init 开始依次以 f 累积每个元素
+func Reduce[T, R any](s []T, init R, f func(acc R, item T) R) R {
+	acc := init
+	for _, item := range s {
+		acc = f(acc, item)
+	}
+	return acc
+}
+
+// Uniq 去重，保留每个元素第一次出现的顺序
+func Uniq[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, item := range s {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
+
+// Chunk 将切片按 size 分块，最后一块可能不足 size；size 小于1时 panic
+func Chunk[T any](s []T, size int) [][]T {
+	if size < 1 {
+		panic("sliceutil: chunk size must be positive")
+	}
+	out := make([][]T, 0, (len(s)+size-1)/size)
+	for size < len(s) {
+		s, out = s[size:], append(out, s[:size:size])
+	}
+	if len(s) > 0 {
+		out = append(out, s)
+	}
+	return out
+}
+
+// GroupBy 按 key 返回的键对元素分组，组内保持原有顺序
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	out := make(map[K][]T)
+	for _, item := range s {
+		k := key(item)
+		out[k] = append(out[k], item)
+	}
+	return out
+}
+
+// Partition 将切片分为使 f 返回true和false的两部分
+func Partition[T any](s []T, f func(T) bool) (matched, rest []T) {
+	for _, item := range s {
+		if f(item) {
+			matched = append(matched, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	return
+}
+
+// Pair 是 Zip 返回的元素对
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip 将两个切片按索引配对，长度取两者中较短的
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	out := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		out[i] = Pair[A, B]{a[i], b[i]}
+	}
+	return out
+}
+
+// Flatten 将二维切片展开为一维
+func Flatten[T any](s [][]T) []T {
+	n := 0
+	for _, item := range s {
+		n += len(item)
+	}
+	out := make([]T, 0, n)
+	for _, item := range s {
+		out = append(out, item...)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+//go:build go1.18
+// +build go1.18
+
+package sliceutil
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSlice(t *testing.T) {
+	s := []int{1, 2, 3, 2, 4, 5}
+	if !Contains(s, 3) || Contains(s, 9) {
+		t.Fatal("Contains error")
+	}
+	if Index(s, 2) != 1 || Index(s, 9) != -1 {
+		t.Fatal("Index error")
+	}
+	if IndexFunc(s, func(v int) bool { return v > 3 }) != 4 {
+		t.Fatal("IndexFunc error")
+	}
+	even := func(v int) bool { return v%2 == 0 }
+	if !reflect.DeepEqual(Filter(s, even), []int{2, 2, 4}) {
+		t.Fatal("Filter error")
+	}
+	if !reflect.DeepEqual(Map([]int{1, 2}, strconv.Itoa), []string{"1", "2"}) {
+		t.Fatal("Map error")
+	}
+	if Reduce(s, 0, func(acc, v int) int { return acc + v }) != 17 {
+		t.Fatal("Reduce error")
+	}
+	if !reflect.DeepEqual(Uniq(s), []int{1, 2, 3, 4, 5}) {
+		t.Fatal("Uniq error")
+	}
+	if !reflect.DeepEqual(Chunk(s, 4), [][]int{{1, 2, 3, 2}, {4, 5}}) {
+		t.Fatal("Chunk error")
+	}
+	if len(Chunk([]int{}, 2)) != 0 {
+		t.Fatal("Chunk empty error")
+	}
+	g := GroupBy(s, even)
+	if len(g[true]) != 3 || len(g[false]) != 3 {
+		t.Fatal("GroupBy error")
+	}
+	m, r := Partition(s, even)
+	if len(m) != 3 || len(r) != 3 || r[0] != 1 {
+		t.Fatal("Partition error")
+	}
+	z := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	if len(z) != 2 || z[1].First != "b" || z[1].Second != 2 {
+		t.Fatal("Zip error")
+	}
+	if !reflect.DeepEqual(Flatten([][]int{{1}, {}, {2, 3}}), []int{1, 2, 3}) {
+		t.Fatal("Flatten error")
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	s := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for i := 0; i < b.N; i++ {
+		Contains(s, "g")
+	}
+}
@@ -0,0 +1,216 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package snowflake 类 Snowflake 的分布式64位唯一ID生成器，生成的ID按时间大致有序（k-sortable）。
+//
+// ID 由高到低依次为：1位符号位（恒为0）、时间戳（毫秒，自 Epoch 起）、工作节点号、序列号，
+// 工作节点号和序列号的位数可配置。
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultWorkerBits   = 10
+	DefaultSequenceBits = 12
+)
+
+// DefaultEpoch 默认起始时间 2021-01-01 00:00:00 UTC
+var DefaultEpoch = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	// 时钟回拨超过允许的等待时间
+	ErrClockRollback = errors.New("snowflake: clock moved backwards")
+	// 时间戳超出可表示的范围
+	ErrTimeOverflow = errors.New("snowflake: timestamp overflow")
+	// 配置的位数无效
+	ErrInvalidBits = errors.New("snowflake: invalid bits")
+	// 工作节点号租约已丢失，继续生成可能与其他节点重复
+	ErrLeaseLost = errors.New("snowflake: worker lease lost")
+)
+
+// Options 生成器配置，零值字段使用默认值
+type Options struct {
+	// Epoch 起始时间，不能晚于当前时间
+	Epoch time.Time
+	// WorkerBits 工作节点号位数，默认10（最多1024个节点）
+	WorkerBits uint8
+	// SequenceBits 每毫秒序列号位数，默认12（每毫秒4096个ID）
+	SequenceBits uint8
+	// RollbackWait 时钟回拨在此时间内时等待时钟追上，否则返回 ErrClockRollback；为0时总是返回错误
+	RollbackWait time.Duration
+}
+
+// WorkerLeaser 工作节点号租约，用于自动分配节点号，如 redigo.WorkerLease
+type WorkerLeaser interface {
+	// Lease 在 [0, maxWorkers) 中申请一个未被占用的节点号
+	Lease(maxWorkers int64) (int64, error)
+	// Release 释放节点号
+	Release() error
+	// Lost 返回租约丢失（如续期失败导致过期）时关闭的通道
+	Lost() <-chan struct{}
+}
+
+// Node 是一个ID生成节点，并发安全
+type Node struct {
+	mu       sync.Mutex
+	epoch    time.Time
+	worker   int64
+	workBits uint8
+	seqBits  uint8
+	seqMask  int64
+	maxTime  int64
+	wait     time.Duration
+	lastMs   int64
+	seq      int64
+	leaser   WorkerLeaser
+	lost     <-chan struct{}
+	now      func() time.Time
+}
+
+// ID 是解码后的ID各部分
+type ID struct {
+	Time     time.Time
+	Worker   int64
+	Sequence int64
+}
+
+// New 创建工作节点号为 worker 的生成器
+func New(worker int64, opts Options) (*Node, error) {
+	if opts.Epoch.IsZero() {
+		opts.Epoch = DefaultEpoch
+	}
+	if opts.WorkerBits == 0 {
+		opts.WorkerBits = DefaultWorkerBits
+	}
+	if opts.SequenceBits == 0 {
+		opts.SequenceBits = DefaultSequenceBits
+	}
+	if int(opts.WorkerBits)+int(opts.SequenceBits) > 31 {
+		return nil, ErrInvalidBits
+	}
+	if max := int64(1) << opts.WorkerBits; worker < 0 || worker >= max {
+		return nil, fmt.Errorf("snowflake: worker must be in [0, %d)", max)
+	}
+	if opts.Epoch.After(time.Now()) {
+		return nil, errors.New("snowflake: epoch is in the future")
+	}
+	return &Node{
+		epoch:    opts.Epoch,
+		worker:   worker,
+		workBits: opts.WorkerBits,
+		seqBits:  opts.SequenceBits,
+		seqMask:  int64(1)<<opts.SequenceBits - 1,
+		maxTime:  int64(1)<<(63-opts.WorkerBits-opts.SequenceBits) - 1,
+		wait:     opts.RollbackWait,
+		lastMs:   -1,
+		now:      time.Now,
+	}, nil
+}
+
+// NewWithLeaser 通过 leaser 申请工作节点号并创建生成器，Close 时释放节点号。
+// 租约丢失后 Generate 返回 ErrLeaseLost，需要重新创建生成器
+func NewWithLeaser(leaser WorkerLeaser, opts Options) (*Node, error) {
+	bits := opts.WorkerBits
+	if bits == 0 {
+		bits = DefaultWorkerBits
+	}
+	worker, err := leaser.Lease(int64(1) << bits)
+	if err != nil {
+		return nil, err
+	}
+	n, err := New(worker, opts)
+	if err != nil {
+		leaser.Release()
+		return nil, err
+	}
+	n.leaser = leaser
+	n.lost = leaser.Lost()
+	return n, nil
+}
+
+// Worker 返回工作节点号
+func (n *Node) Worker() int64 {
+	return n.worker
+}
+
+// elapsed 返回自 Epoch 起的毫秒数
+func (n *Node) elapsed() int64 {
+	return int64(n.now().Sub(n.epoch) / time.Millisecond)
+}
+
+// Generate 生成一个ID。同一毫秒内序列号用尽时等待下一毫秒，租约丢失时返回 ErrLeaseLost
+func (n *Node) Generate() (int64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	select {
+	case <-n.lost:
+		return 0, ErrLeaseLost
+	default:
+	}
+
+	ms := n.elapsed()
+	if ms < n.lastMs {
+		back := time.Duration(n.lastMs-ms) * time.Millisecond
+		if back > n.wait {
+			return 0, ErrClockRollback
+		}
+		for ms < n.lastMs {
+			time.Sleep(time.Duration(n.lastMs-ms) * time.Millisecond)
+			ms = n.elapsed()
+		}
+	}
+	if ms == n.lastMs {
+		n.seq = (n.seq + 1) & n.seqMask
+		if n.seq == 0 {
+			for ms <= n.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				ms = n.elapsed()
+			}
+		}
+	} else {
+		n.seq = 0
+	}
+	if ms > n.maxTime {
+		return 0, ErrTimeOverflow
+	}
+	n.lastMs = ms
+	return ms<<(n.workBits+n.seqBits) | n.worker<<n.seqBits | n.seq, nil
+}
+
+// Decode 按本节点的配置将ID解码为时间、工作节点号和序列号
+func (n *Node) Decode(id int64) ID {
+	ms := id >> (n.workBits + n.seqBits)
+	return ID{
+		Time:     n.epoch.Add(time.Duration(ms) * time.Millisecond),
+		Worker:   (id >> n.seqBits) & (int64(1)<<n.workBits - 1),
+		Sequence: id & n.seqMask,
+	}
+}
+
+// Close 释放通过 NewWithLeaser 申请的工作节点号，之后不应再使用此节点
+func (n *Node) Close() error {
+	if n.leaser != nil {
+		return n.leaser.Release()
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	n, err := New(5, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last int64
+			for j := 0; j < 2000; j++ {
+				id, err := n.Generate()
+				if err != nil || id <= last {
+					t.Error("id should increase")
+					return
+				}
+				last = id
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 16000 {
+		t.Fatal("duplicate id")
+	}
+
+	id, _ := n.Generate()
+	d := n.Decode(id)
+	if d.Worker != 5 || time.Since(d.Time) > time.Second || time.Since(d.Time) < 0 {
+		t.Fatalf("decode error: %+v", d)
+	}
+
+	if _, err = New(1024, Options{}); err == nil {
+		t.Fatal("worker out of range")
+	}
+	if _, err = New(0, Options{WorkerBits: 20, SequenceBits: 20}); err != ErrInvalidBits {
+		t.Fatal("invalid bits")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	now := time.Now()
+	n, _ := New(1, Options{WorkerBits: 4, SequenceBits: 4})
+	n.now = func() time.Time { return now }
+	if _, err := n.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(-time.Second)
+	if _, err := n.Generate(); err != ErrClockRollback {
+		t.Fatal("should raise ErrClockRollback")
+	}
+
+	n.wait = 10 * time.Millisecond
+	base := time.Now()
+	start := base.Add(5 * time.Millisecond)
+	n.lastMs = -1
+	n.now = func() time.Time { return start.Add(time.Since(base)) }
+	n.Generate()
+	start = start.Add(-5 * time.Millisecond)
+	if _, err := n.Generate(); err != nil {
+		t.Fatal("small rollback should wait")
+	}
+}
+
+type fakeLeaser struct {
+	released bool
+	lost     chan struct{}
+}
+
+func (f *fakeLeaser) Lease(max int64) (int64, error) {
+	if max != 256 {
+		return 0, errors.New("max workers error")
+	}
+	return 7, nil
+}
+
+func (f *fakeLeaser) Release() error {
+	f.released = true
+	return nil
+}
+
+func (f *fakeLeaser) Lost() <-chan struct{} {
+	return f.lost
+}
+
+func TestLeaser(t *testing.T) {
+	l := &fakeLeaser{lost: make(chan struct{})}
+	n, err := NewWithLeaser(l, Options{WorkerBits: 8})
+	if err != nil || n.Worker() != 7 {
+		t.Fatal("leaser error")
+	}
+	if _, err = n.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	close(l.lost)
+	if _, err = n.Generate(); err != ErrLeaseLost {
+		t.Fatal("generate should fail after lease lost")
+	}
+	n.Close()
+	if !l.released {
+		t.Fatal("lease should be released")
+	}
+}
@@ -0,0 +1,174 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"unicode"
+)
+
+// SubString 的索引超出范围（严格模式）
+var ErrIndexOutOfRange = errors.New("index out of range")
+
+// SubStrMode 截取字符串时的计数单位
+type SubStrMode int
+
+const (
+	// RuneMode 按 Unicode 码点（rune）计数，与 SubStr 相同
+	RuneMode SubStrMode = iota
+	// ByteMode 按字节计数，无需解码因此最快，但可能截断多字节字符
+	ByteMode
+	// GraphemeMode 按字素簇计数，不会拆开 emoji 序列、组合字符等
+	GraphemeMode
+)
+
+// SubStrOption 是 SubString 的选项，零值表示按 rune 计数、超出范围时自动截断
+type SubStrOption struct {
+	Mode SubStrMode
+	// Strict 为true时索引超出范围返回 ErrIndexOutOfRange，否则自动截断到有效范围
+	Strict bool
+}
+
+// SubString 截取字符串，start、end是起始、终点（不包含）索引，
+// 支持Python风格的负数索引（-1表示最后一个单位）
+func SubString(str string, start, end int, opt SubStrOption) (string, error) {
+	switch opt.Mode {
+	case ByteMode:
+		start, end, err := normalizeRange(start, end, len(str), opt.Strict)
+		if err != nil {
+			return "", err
+		}
+		return str[start:end], nil
+	case GraphemeMode:
+		bounds := graphemeBounds(str)
+		start, end, err := normalizeRange(start, end, len(bounds)-1, opt.Strict)
+		if err != nil {
+			return "", err
+		}
+		return str[bounds[start]:bounds[end]], nil
+	}
+	rs := []rune(str)
+	start, end, err := normalizeRange(start, end, len(rs), opt.Strict)
+	if err != nil {
+		return "", err
+	}
+	return string(rs[start:end]), nil
+}
+
+// normalizeRange 将负数索引转换为正数，并按模式检查或截断到 [0, length]
+func normalizeRange(start, end, length int, strict bool) (int, int, error) {
+	if start < 0 {
+		start += length
+	}
+	if end < 0 {
+		end += length
+	}
+	if strict {
+		if start < 0 || end < 0 || start > length || end > length || start > end {
+			return 0, 0, ErrIndexOutOfRange
+		}
+		return start, end, nil
+	}
+	start = clamp(start, 0, length)
+	end = clamp(end, 0, length)
+	if start > end {
+		start = end
+	}
+	return start, end, nil
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// SplitGraphemes 将字符串拆分为字素簇（用户感知的字符）
+func SplitGraphemes(str string) []string {
+	bounds := graphemeBounds(str)
+	out := make([]string, len(bounds)-1)
+	for i := range out {
+		out[i] = str[bounds[i]:bounds[i+1]]
+	}
+	return out
+}
+
+// graphemeBounds 返回每个字素簇起始的字节偏移，最后一个元素为 len(str)。
+// 这是 UAX #29 的简化实现，覆盖 CRLF、组合字符、变体选择符、emoji修饰符、
+// 零宽连接符序列、区域指示符（国旗）和标签序列
+func graphemeBounds(str string) []int {
+	bounds := make([]int, 0, len(str)+1)
+	var prev rune = -1
+	ri := 0
+	for i, r := range str {
+		if prev >= 0 && !graphemeExtends(prev, r, ri) {
+			bounds = append(bounds, i)
+			ri = 0
+		} else if prev < 0 {
+			bounds = append(bounds, i)
+		}
+		if isRegionalIndicator(r) {
+			ri++
+		} else {
+			ri = 0
+		}
+		prev = r
+	}
+	return append(bounds, len(str))
+}
+
+// graphemeExtends 判断 r 是否与前一个字符 prev 属于同一个字素簇，ri 是此前连续的区域指示符个数
+func graphemeExtends(prev, r rune, ri int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == '\r' || prev == '\n' || r == '\r' || r == '\n':
+		return false
+	case prev == zwj:
+		// 零宽连接符之后的字符，如家庭 emoji 中的各个成员
+		return true
+	case r == zwj, r == zwnj:
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF:
+		// 变体选择符
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		// emoji 肤色修饰符
+		return true
+	case r >= 0xE0020 && r <= 0xE007F:
+		// 标签字符（如苏格兰旗）
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return ri%2 == 1
+	}
+	return false
+}
+
+const (
+	zwj  = '\u200d'
+	zwnj = '\u200c'
+)
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,52 @@
+package gtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubString(t *testing.T) {
+	s := "你好，世界"
+	cases := []struct {
+		start, end int
+		want       string
+	}{
+		{0, 2, "你好"},
+		{-2, 5, "世界"},
+		{-3, -1, "，世"},
+		{3, 100, "世界"},
+		{-100, 1, "你"},
+		{4, 2, ""},
+	}
+	for _, c := range cases {
+		got, err := SubString(s, c.start, c.end, SubStrOption{})
+		if err != nil || got != c.want {
+			t.Fatalf("SubString(%d, %d) = %q, want %q", c.start, c.end, got, c.want)
+		}
+	}
+
+	if _, err := SubString(s, 3, 100, SubStrOption{Strict: true}); err != ErrIndexOutOfRange {
+		t.Fatal("strict mode should raise ErrIndexOutOfRange")
+	}
+	if _, err := SubString(s, 4, 2, SubStrOption{Strict: true}); err != ErrIndexOutOfRange {
+		t.Fatal("strict mode start > end")
+	}
+	if got, _ := SubString("abcd", -3, -1, SubStrOption{Mode: ByteMode}); got != "bc" {
+		t.Fatal("byte mode error")
+	}
+
+	emoji := "a👨‍👩‍👧é🇨🇳🇺🇸👍🏽\r\n"
+	if got := SplitGraphemes(emoji); !reflect.DeepEqual(got, []string{"a", "👨‍👩‍👧", "é", "🇨🇳", "🇺🇸", "👍🏽", "\r\n"}) {
+		t.Fatalf("SplitGraphemes error: %q", got)
+	}
+	if got, _ := SubString(emoji, 1, 3, SubStrOption{Mode: GraphemeMode}); got != "👨‍👩‍👧é" {
+		t.Fatalf("grapheme mode error: %q", got)
+	}
+	if got, _ := SubString(emoji, -3, -1, SubStrOption{Mode: GraphemeMode, Strict: true}); got != "🇺🇸👍🏽" {
+		t.Fatalf("grapheme mode negative error: %q", got)
+	}
+
+	if SubStr(s, 3, 6) != "" || SubStr(s, 2, 1) != "" || SubStr(s, 0, 5) != s {
+		t.Fatal("SubStr compatibility error")
+	}
+}
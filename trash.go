@@ -0,0 +1,144 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 回收站中不存在该项
+var ErrTrashNotFound = errors.New("trash item not found")
+
+const (
+	trashDataName = "data"
+	trashInfoName = "info.json"
+)
+
+// TrashItem 是回收站中的一项，每项是回收站目录下的一个子目录，
+// 其中 data 为被删除的文件或目录，info.json 记录原路径和删除时间
+type TrashItem struct {
+	ID           string    `json:"id"`
+	OriginalPath string    `json:"original_path"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+// moveToTrash 将 path 移动到回收站 trashDir 中
+func moveToTrash(path, trashDir string) error {
+	trash, err := realPath(trashDir)
+	if err != nil {
+		return err
+	}
+	if isWithin(path, trash) {
+		return &os.PathError{Op: "remove", Path: path, Err: errors.New("trash dir is inside the removed path")}
+	}
+	if _, err = CreateAllDirWith(trash, DirMode(0700)); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	suffix, err := RandomString(6, AlphabetAlphaNum)
+	if err != nil {
+		return err
+	}
+	item := TrashItem{
+		ID:           now.Format("20060102T150405") + "-" + suffix,
+		OriginalPath: path,
+		DeletedAt:    now,
+	}
+	dir := filepath.Join(trash, item.ID)
+	if err = os.Mkdir(dir, 0700); err != nil {
+		return err
+	}
+	info, err := json.Marshal(item)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, trashInfoName), info, 0600)
+	}
+	if err == nil {
+		err = os.Rename(path, filepath.Join(dir, trashDataName))
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+	}
+	return err
+}
+
+// ListTrash 列出回收站中的所有项，按删除时间排序，无法识别的子目录会被忽略
+func ListTrash(trashDir string) ([]TrashItem, error) {
+	names, err := readDirNames(trashDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	items := make([]TrashItem, 0, len(names))
+	for _, name := range names {
+		item, err := readTrashItem(trashDir, name)
+		if err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.Before(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+func readTrashItem(trashDir, id string) (item TrashItem, err error) {
+	raw, err := ioutil.ReadFile(filepath.Join(trashDir, id, trashInfoName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = ErrTrashNotFound
+		}
+		return
+	}
+	if err = json.Unmarshal(raw, &item); err != nil {
+		return
+	}
+	item.ID = id
+	return
+}
+
+// RestoreTrash 将回收站中的一项恢复到原路径并返回原路径，原路径已存在时返回错误，
+// 原路径的上级目录不存在时会自动创建
+func RestoreTrash(trashDir, id string) (string, error) {
+	if id == "" || filepath.Base(id) != id {
+		return "", ErrTrashNotFound
+	}
+	item, err := readTrashItem(trashDir, id)
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Lstat(item.OriginalPath); err == nil {
+		return "", &os.PathError{Op: "restore", Path: item.OriginalPath, Err: os.ErrExist}
+	}
+	if err = CreateAllDir(filepath.Dir(item.OriginalPath)); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(trashDir, id)
+	if err = os.Rename(filepath.Join(dir, trashDataName), item.OriginalPath); err != nil {
+		return "", err
+	}
+	return item.OriginalPath, os.RemoveAll(dir)
+}
@@ -0,0 +1,293 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// 值中存在循环引用，无法计算哈希
+var ErrHashCycle = errors.New("cannot hash cyclic value")
+
+// UnhashableError 表示值中包含无法计算哈希的类型（函数、通道、unsafe.Pointer）
+type UnhashableError struct {
+	Type reflect.Type
+}
+
+func (e *UnhashableError) Error() string {
+	return "gtc: cannot hash " + e.Type.String()
+}
+
+// 每种值编码前的类型标记，避免不同类型的编码相互冲突
+const (
+	tagNil byte = iota
+	tagBool
+	tagInt
+	tagUint
+	tagFloat
+	tagComplex
+	tagString
+	tagBytes
+	tagText
+	tagList
+	tagMap
+	tagStruct
+)
+
+// HashValue 使用 algo 计算任意值的确定性摘要，返回十六进制字符串，可直接作为缓存键，如：
+//
+//	key, err := gtc.HashValue(query, gtc.AlgoSHA1)
+//	db.Set("cache:"+key, result)
+//
+// 规则如下：
+//
+// 指针、接口会解引用，因此 &v 与 v 的结果相同，nil 指针与 nil 接口相同；
+// 整数按有符号、无符号分别统一为64位，因此 int(1) 与 int64(1) 相同，浮点数同理；
+// map 按键的编码排序，与遍历顺序无关；
+// 结构体只计算导出字段，按字段名和值编码，标签 `hash:"-"` 的字段会被跳过；
+// 实现了 encoding.TextMarshaler 的类型（如 time.Time）按其文本计算。
+//
+// 值中有循环引用时返回 ErrHashCycle，有函数、通道时返回 *UnhashableError
+func HashValue(v interface{}, algo HashAlgo) (string, error) {
+	fn, err := algo.hashFunc()
+	if err != nil {
+		return "", err
+	}
+	h := fn()
+	e := &valueEncoder{visiting: make(map[visitKey]bool)}
+	if err = e.encode(h, reflect.ValueOf(v)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// visitKey 标识正在编码的引用类型值，用于检测循环引用
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type valueEncoder struct {
+	visiting map[visitKey]bool
+	scratch  [8]byte
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func (e *valueEncoder) writeUint(w io.Writer, tag byte, n uint64) {
+	w.Write([]byte{tag})
+	binary.BigEndian.PutUint64(e.scratch[:], n)
+	w.Write(e.scratch[:])
+}
+
+func (e *valueEncoder) writeBytes(w io.Writer, tag byte, b []byte) {
+	e.writeUint(w, tag, uint64(len(b)))
+	w.Write(b)
+}
+
+// floatBits 返回浮点数的规范化位表示：-0 与 0 相同，所有 NaN 相同
+func floatBits(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	if math.IsNaN(f) {
+		return math.Float64bits(math.NaN())
+	}
+	return math.Float64bits(f)
+}
+
+// enter 标记进入引用类型的值，若已在编码路径上则说明存在循环引用
+func (e *valueEncoder) enter(v reflect.Value) (leave func(), err error) {
+	k := visitKey{v.Pointer(), v.Type()}
+	if e.visiting[k] {
+		return nil, ErrHashCycle
+	}
+	e.visiting[k] = true
+	return func() { delete(e.visiting, k) }, nil
+}
+
+func (e *valueEncoder) encode(w io.Writer, v reflect.Value) error {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			break
+		}
+		if v.Kind() == reflect.Ptr {
+			if v.Type().Implements(textMarshalerType) {
+				break
+			}
+			leave, err := e.enter(v)
+			if err != nil {
+				return err
+			}
+			defer leave()
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || ((v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()) {
+		w.Write([]byte{tagNil})
+		return nil
+	}
+
+	if m, ok := textMarshaler(v); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return err
+		}
+		e.writeBytes(w, tagText, text)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		var b uint64
+		if v.Bool() {
+			b = 1
+		}
+		e.writeUint(w, tagBool, b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeUint(w, tagInt, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeUint(w, tagUint, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.writeUint(w, tagFloat, floatBits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		e.writeUint(w, tagComplex, floatBits(real(c)))
+		binary.BigEndian.PutUint64(e.scratch[:], floatBits(imag(c)))
+		w.Write(e.scratch[:])
+	case reflect.String:
+		e.writeBytes(w, tagString, []byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				w.Write([]byte{tagNil})
+				return nil
+			}
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				e.writeBytes(w, tagBytes, v.Bytes())
+				return nil
+			}
+			if v.Len() > 0 {
+				leave, err := e.enter(v)
+				if err != nil {
+					return err
+				}
+				defer leave()
+			}
+		}
+		e.writeUint(w, tagList, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(w, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			w.Write([]byte{tagNil})
+			return nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return err
+		}
+		defer leave()
+		return e.encodeMap(w, v)
+	case reflect.Struct:
+		return e.encodeStruct(w, v)
+	default:
+		return &UnhashableError{v.Type()}
+	}
+	return nil
+}
+
+// textMarshaler 返回 v 或其地址实现的 encoding.TextMarshaler
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	if !v.CanInterface() {
+		return nil, false
+	}
+	if v.Type().Implements(textMarshalerType) {
+		return v.Interface().(encoding.TextMarshaler), true
+	}
+	if !reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		return nil, false
+	}
+	// 指针接收者的 MarshalText：不可寻址的值先复制为可寻址的，使 v 与 &v 结果相同
+	if !v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+	return v.Addr().Interface().(encoding.TextMarshaler), true
+}
+
+// encodeMap 分别编码每个键值对，按键的编码排序后写入
+func (e *valueEncoder) encodeMap(w io.Writer, v reflect.Value) error {
+	type entry struct {
+		key, value []byte
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var kb, vb bytes.Buffer
+		if err := e.encode(&kb, iter.Key()); err != nil {
+			return err
+		}
+		if err := e.encode(&vb, iter.Value()); err != nil {
+			return err
+		}
+		entries = append(entries, entry{kb.Bytes(), vb.Bytes()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	e.writeUint(w, tagMap, uint64(len(entries)))
+	for _, en := range entries {
+		w.Write(en.key)
+		w.Write(en.value)
+	}
+	return nil
+}
+
+func (e *valueEncoder) encodeStruct(w io.Writer, v reflect.Value) error {
+	t := v.Type()
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" && f.Tag.Get("hash") != "-" {
+			n++
+		}
+	}
+	e.writeUint(w, tagStruct, uint64(n))
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("hash") == "-" {
+			continue
+		}
+		e.writeBytes(w, tagString, []byte(f.Name))
+		if err := e.encode(w, v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
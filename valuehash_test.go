@@ -0,0 +1,123 @@
+package gtc
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type hashUser struct {
+	Name    string
+	Age     int
+	Tags    []string
+	Meta    map[string]interface{}
+	Created time.Time
+	Session string `hash:"-"`
+	secret  string
+}
+
+type hashText struct {
+	N int
+}
+
+func (h *hashText) MarshalText() ([]byte, error) {
+	return []byte("text"), nil
+}
+
+type hashNode struct {
+	Name string
+	Next *hashNode
+}
+
+func mustHash(t *testing.T, v interface{}) string {
+	t.Helper()
+	h, err := HashValue(v, AlgoSHA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHashValue(t *testing.T) {
+	created := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	u1 := hashUser{
+		Name: "a", Age: 18, Tags: []string{"x", "y"}, Created: created,
+		Meta: map[string]interface{}{"k1": 1, "k2": []int{1, 2}, "k3": nil},
+	}
+	h1 := mustHash(t, u1)
+	if len(h1) != 40 {
+		t.Fatal("sha1 hex length error")
+	}
+	for i := 0; i < 20; i++ {
+		u2 := u1
+		u2.Meta = map[string]interface{}{"k3": nil, "k2": []int{1, 2}, "k1": 1}
+		u2.Session, u2.secret = "ignored", "ignored"
+		if mustHash(t, u2) != h1 || mustHash(t, &u2) != h1 {
+			t.Fatal("hash should be stable and ignore map order, skipped and unexported fields")
+		}
+	}
+
+	u3 := u1
+	u3.Created = created.Add(time.Second)
+	if mustHash(t, u3) == h1 {
+		t.Fatal("time.Time should be hashed by value")
+	}
+	u3 = u1
+	u3.Tags = []string{"y", "x"}
+	if mustHash(t, u3) == h1 {
+		t.Fatal("slice order should matter")
+	}
+
+	distinct := []interface{}{nil, "", 0, uint(0), false, 0.0, []byte{}, []string{}, map[string]int{}, "1", 1, []string{"ab", "c"}, []string{"a", "bc"}}
+	seen := make(map[string]int)
+	for i, v := range distinct {
+		h := mustHash(t, v)
+		if j, ok := seen[h]; ok {
+			t.Fatalf("hash collision between %#v and %#v", distinct[j], v)
+		}
+		seen[h] = i
+	}
+	if mustHash(t, int8(7)) != mustHash(t, int64(7)) || mustHash(t, math.Copysign(0, -1)) != mustHash(t, 0.0) {
+		t.Fatal("numbers should be normalized")
+	}
+	// 指针接收者的 MarshalText 对值和指针同样生效
+	text := mustHash(t, &hashText{1})
+	if mustHash(t, hashText{1}) != text || mustHash(t, hashText{2}) != text || mustHash(t, []hashText{{1}}) != mustHash(t, []*hashText{{1}}) {
+		t.Fatal("pointer receiver MarshalText should apply to values")
+	}
+	var np *hashNode
+	if mustHash(t, np) != mustHash(t, nil) {
+		t.Fatal("nil pointer should equal nil")
+	}
+
+	md5Hash, _ := HashValue("a", AlgoMD5)
+	if len(md5Hash) != 32 {
+		t.Fatal("md5 hex length error")
+	}
+	if _, err := HashValue("a", HashAlgo("crc")); err != ErrUnsupportedAlgo {
+		t.Fatal("unsupported algo")
+	}
+}
+
+func TestHashValueError(t *testing.T) {
+	n := &hashNode{Name: "a"}
+	n.Next = &hashNode{Name: "b", Next: n}
+	if _, err := HashValue(n, AlgoMD5); err != ErrHashCycle {
+		t.Fatalf("cycle should be detected: %v", err)
+	}
+	m := map[string]interface{}{}
+	m["self"] = m
+	if _, err := HashValue(m, AlgoMD5); err != ErrHashCycle {
+		t.Fatal("map cycle should be detected")
+	}
+	// 共享但不成环的指针不是循环引用
+	shared := &hashNode{Name: "s"}
+	if _, err := HashValue([]*hashNode{shared, shared}, AlgoMD5); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := HashValue(map[string]interface{}{"f": func() {}}, AlgoMD5)
+	if e, ok := err.(*UnhashableError); !ok || e.Type.String() != "func()" {
+		t.Fatalf("func should be unhashable: %v", err)
+	}
+}
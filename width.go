@@ -0,0 +1,279 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges 东亚宽度（East Asian Width）为 W 或 F 的主要码点区间，已按升序排列
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4},
+	{0x17000, 0x18AFF}, {0x1B000, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F202}, {0x1F210, 0x1F23B},
+	{0x1F240, 0x1F248}, {0x1F250, 0x1F251}, {0x1F260, 0x1F265}, {0x1F300, 0x1F320},
+	{0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA},
+	{0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4}, {0x1F3F8, 0x1F43E},
+	{0x1F440, 0x1F440}, {0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E},
+	{0x1F550, 0x1F567}, {0x1F57A, 0x1F57A}, {0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4},
+	{0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC}, {0x1F6D0, 0x1F6D2},
+	{0x1F6D5, 0x1F6D7}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC}, {0x1F7E0, 0x1F7EB},
+	{0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF}, {0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// RuneWidth 返回单个字符的显示宽度：控制字符、组合字符等为0，
+// 东亚宽字符、全角字符为2，其余（含东亚宽度为 Ambiguous 的字符）为1
+func RuneWidth(r rune) int {
+	if r < 0x20 || (r >= 0x7F && r < 0xA0) {
+		return 0
+	}
+	if r < 0x1100 {
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			return 0
+		}
+		return 1
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	if i < len(wideRanges) && wideRanges[i][0] <= r {
+		return 2
+	}
+	return 1
+}
+
+// graphemeWidth 返回一个字素簇的显示宽度，以首个字符为准，
+// emoji 变体选择符（U+FE0F）和国旗序列按2计算
+func graphemeWidth(g string) int {
+	w := -1
+	for _, r := range g {
+		if w < 0 {
+			w = RuneWidth(r)
+			if isRegionalIndicator(r) {
+				return 2
+			}
+			continue
+		}
+		if r == 0xFE0F {
+			return 2
+		}
+	}
+	if w < 0 {
+		return 0
+	}
+	return w
+}
+
+// DisplayWidth 返回字符串在等宽终端中的显示宽度，按东亚宽度规则计算，中文等宽字符占2列
+func DisplayWidth(s string) int {
+	w := 0
+	for _, g := range SplitGraphemes(s) {
+		w += graphemeWidth(g)
+	}
+	return w
+}
+
+// TruncateWidth 将字符串截断到不超过 width 的显示宽度，发生截断时在末尾加上 ellipsis（计入宽度）。
+// 不会拆开字素簇；ellipsis 本身超过 width 时不添加
+func TruncateWidth(s string, width int, ellipsis string) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	ew := DisplayWidth(ellipsis)
+	if ew > width {
+		ellipsis, ew = "", 0
+	}
+	var b strings.Builder
+	w := 0
+	for _, g := range SplitGraphemes(s) {
+		gw := graphemeWidth(g)
+		if w+gw > width-ew {
+			break
+		}
+		b.WriteString(g)
+		w += gw
+	}
+	return b.String() + ellipsis
+}
+
+// PadRight 在右侧填充空格使显示宽度达到 width，已超过时原样返回
+func PadRight(s string, width int) string {
+	if n := width - DisplayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft 在左侧填充空格使显示宽度达到 width，已超过时原样返回
+func PadLeft(s string, width int) string {
+	if n := width - DisplayWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Center 在两侧填充空格使字符串居中，无法平分时右侧多一个空格
+func Center(s string, width int) string {
+	n := width - DisplayWidth(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+}
+
+// 不能出现在行首的标点（避头）
+const noLineStart = ",.;:!?)]}%、。，；：？！）」』】〉》〕］｝”’…—ー・ぁぃぅぇぉっゃゅょァィゥェォッャュョ"
+
+// 不能出现在行尾的标点（避尾）
+const noLineEnd = "([{$（「『【〈《〔［｛“‘"
+
+// wrapUnit 是折行的最小单位：一个单词或一个CJK字符（及其附着的标点）
+type wrapUnit struct {
+	text  string
+	space bool // 与前一个单位之间有空白
+}
+
+// isCJKBreakable 判断字素簇前后是否允许直接断行（中日韩文字、全角字符等）
+func isCJKBreakable(g string) bool {
+	r, _ := utf8.DecodeRuneInString(g)
+	return RuneWidth(r) == 2 || unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// splitWrapUnits 将一段文字拆分为折行单位，并按避头尾规则合并标点
+func splitWrapUnits(s string) []wrapUnit {
+	var units []wrapUnit
+	var word strings.Builder
+	space := false
+	flush := func() {
+		if word.Len() > 0 {
+			units = append(units, wrapUnit{word.String(), space})
+			word.Reset()
+			space = false
+		}
+	}
+	for _, g := range SplitGraphemes(s) {
+		r, _ := utf8.DecodeRuneInString(g)
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+			space = true
+		case isCJKBreakable(g):
+			flush()
+			units = append(units, wrapUnit{g, space})
+			space = false
+		default:
+			word.WriteString(g)
+		}
+	}
+	flush()
+
+	merged := make([]wrapUnit, 0, len(units))
+	for i := 0; i < len(units); i++ {
+		u := units[i]
+		n := len(merged)
+		if n > 0 && !u.space && strings.ContainsAny(firstGrapheme(u.text), noLineStart) {
+			merged[n-1].text += u.text
+			continue
+		}
+		if n > 0 && !u.space && strings.ContainsAny(lastGrapheme(merged[n-1].text), noLineEnd) {
+			merged[n-1].text += u.text
+			continue
+		}
+		merged = append(merged, u)
+	}
+	return merged
+}
+
+func firstGrapheme(s string) string {
+	bounds := graphemeBounds(s)
+	return s[:bounds[1]]
+}
+
+func lastGrapheme(s string) string {
+	bounds := graphemeBounds(s)
+	return s[bounds[len(bounds)-2]:]
+}
+
+// WrapWidth 按显示宽度折行，返回各行（不含换行符）。
+// 英文等按单词断行，中日韩文字可在任意字符间断行，并遵循避头尾规则：
+// 句号、逗号、右括号等不出现在行首，左括号、左引号等不出现在行尾。
+// 单个单词超过 width 时会被强制拆开；原有的换行会被保留
+func WrapWidth(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		var line strings.Builder
+		lw := 0
+		push := func() {
+			lines = append(lines, line.String())
+			line.Reset()
+			lw = 0
+		}
+		for _, u := range splitWrapUnits(para) {
+			uw := DisplayWidth(u.text)
+			sw := 0
+			if u.space && lw > 0 {
+				sw = 1
+			}
+			if lw+sw+uw <= width {
+				if sw > 0 {
+					line.WriteString(" ")
+				}
+				line.WriteString(u.text)
+				lw += sw + uw
+				continue
+			}
+			if lw > 0 {
+				push()
+			}
+			if uw <= width {
+				line.WriteString(u.text)
+				lw = uw
+				continue
+			}
+			// 超长单词强制拆开
+			for _, g := range SplitGraphemes(u.text) {
+				gw := graphemeWidth(g)
+				if lw+gw > width && lw > 0 {
+					push()
+				}
+				line.WriteString(g)
+				lw += gw
+			}
+		}
+		push()
+	}
+	return lines
+}
@@ -0,0 +1,63 @@
+package gtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	cases := map[string]int{
+		"abc":  3,
+		"你好":   4,
+		"ｈｉ":   4,
+		"é":   1,
+		"👍🏽":   2,
+		"🇨🇳":   2,
+		"❤️":   2,
+		"a\tb": 2,
+		"한국어":  6,
+		"カタカナ": 8,
+	}
+	for s, w := range cases {
+		if DisplayWidth(s) != w {
+			t.Fatalf("DisplayWidth(%q) = %d, want %d", s, DisplayWidth(s), w)
+		}
+	}
+
+	if TruncateWidth("你好，世界", 7, "...") != "你好..." {
+		t.Fatal("TruncateWidth error")
+	}
+	if TruncateWidth("你好", 4, "…") != "你好" {
+		t.Fatal("TruncateWidth should not truncate")
+	}
+	if TruncateWidth("abcdef", 2, "...") != "ab" {
+		t.Fatal("TruncateWidth without ellipsis error")
+	}
+
+	if PadRight("中文", 6) != "中文  " || PadLeft("中文", 5) != " 中文" {
+		t.Fatal("Pad error")
+	}
+	if Center("中", 7) != "  中   " || Center("中文", 3) != "中文" {
+		t.Fatal("Center error")
+	}
+}
+
+func TestWrapWidth(t *testing.T) {
+	got := WrapWidth("hello world, 你好，世界。", 8)
+	want := []string{"hello", "world,", "你好，世", "界。"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("WrapWidth error: %q", got)
+	}
+
+	got = WrapWidth("中文「引号」测试", 6)
+	want = []string{"中文", "「引", "号」测", "试"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("WrapWidth kinsoku error: %q", got)
+	}
+
+	got = WrapWidth("abcdefgh\nij", 3)
+	want = []string{"abc", "def", "gh", "ij"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("WrapWidth long word error: %q", got)
+	}
+}
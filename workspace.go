@@ -0,0 +1,112 @@
+/*
+   Copyright 2021 Hiroshi.tao
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gtc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 相对路径逃出了工作区（绝对路径或以 .. 跳出根目录）
+var ErrOutsideWorkspace = errors.New("path escapes workspace root")
+
+// Workspace 临时工作区，由 TempWorkspace 创建，使用完毕后调用 Close 递归清理
+type Workspace struct {
+	root   string
+	failed func() bool
+	closed bool
+}
+
+// TempWorkspace 在系统临时目录下创建一个临时工作区，
+// pattern 是目录名前缀（同 os.MkdirTemp，最后一个*会被替换为随机字符串）
+func TempWorkspace(pattern string) (*Workspace, error) {
+	root, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Workspace{root: root}, nil
+}
+
+// Root 返回工作区根目录
+func (w *Workspace) Root() string {
+	return w.root
+}
+
+// Path 返回工作区内相对路径 rel 的完整路径，rel 逃出工作区时 panic
+func (w *Workspace) Path(rel string) string {
+	path, err := w.resolve(rel)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// resolve 返回 rel 的完整路径，rel 为绝对路径或清理后以 .. 跳出根目录时返回 ErrOutsideWorkspace
+func (w *Workspace) resolve(rel string) (string, error) {
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return "", &os.PathError{Op: "resolve", Path: rel, Err: ErrOutsideWorkspace}
+	}
+	clean := filepath.Clean(rel)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", &os.PathError{Op: "resolve", Path: rel, Err: ErrOutsideWorkspace}
+	}
+	return filepath.Join(w.root, clean), nil
+}
+
+// WriteFile 写入工作区内的文件，会自动创建上级目录并覆盖已有文件
+func (w *Workspace) WriteFile(rel string, data []byte) error {
+	path, err := w.resolve(rel)
+	if err != nil {
+		return err
+	}
+	if err = CreateAllDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// CopyIn 将外部文件 src 复制到工作区内的 rel，会自动创建上级目录
+func (w *Workspace) CopyIn(rel, src string) (written int64, err error) {
+	path, err := w.resolve(rel)
+	if err != nil {
+		return
+	}
+	if err = CreateAllDir(filepath.Dir(path)); err != nil {
+		return
+	}
+	return FileCopy(path, src)
+}
+
+// KeepOnFailure 设置失败检测函数，Close 时若其返回true则保留工作区以便排查，
+// 测试中可直接传入 t.Failed
+func (w *Workspace) KeepOnFailure(failed func() bool) {
+	w.failed = failed
+}
+
+// Close 递归删除工作区，多次调用是安全的
+func (w *Workspace) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.failed != nil && w.failed() {
+		return nil
+	}
+	return os.RemoveAll(w.root)
+}
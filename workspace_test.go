@@ -0,0 +1,84 @@
+package gtc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspace(t *testing.T) {
+	ws, err := TempWorkspace("gtc-ws-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsDir(ws.Root()) {
+		t.Fatal("workspace root should be dir")
+	}
+
+	if err = ws.WriteFile("a/b/c.txt", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	text, err := FileReadStr(ws.Path("a/b/c.txt"))
+	if err != nil || text != "hello" {
+		t.Fatal("workspace WriteFile error")
+	}
+
+	n, err := ws.CopyIn("d/go.mod", "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 || !IsCommonFile(ws.Path("d/go.mod")) {
+		t.Fatal("workspace CopyIn error")
+	}
+
+	if err = ws.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if PathExist(ws.Root()) {
+		t.Fatal("workspace should be removed after Close")
+	}
+	if err = ws.Close(); err != nil {
+		t.Fatal("close twice should be safe")
+	}
+
+	keep, err := TempWorkspace("gtc-ws-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keep.KeepOnFailure(func() bool { return true })
+	keep.Close()
+	if !IsDir(keep.Root()) {
+		t.Fatal("workspace should be kept on failure")
+	}
+	os.RemoveAll(keep.Root())
+}
+
+func TestWorkspaceEscape(t *testing.T) {
+	ws, err := TempWorkspace("gtc-ws-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	if ws.Path("a/../b") != filepath.Join(ws.Root(), "b") {
+		t.Fatal("workspace Path error")
+	}
+	for _, rel := range []string{"..", "../x", "a/../../x", filepath.Join(ws.Root(), "x")} {
+		if err = ws.WriteFile(rel, nil); !isOutside(err) {
+			t.Fatalf("WriteFile %q should be refused: %v", rel, err)
+		}
+		if _, err = ws.CopyIn(rel, "go.mod"); !isOutside(err) {
+			t.Fatalf("CopyIn %q should be refused: %v", rel, err)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Path outside workspace should panic")
+		}
+	}()
+	ws.Path("../x")
+}
+
+func isOutside(err error) bool {
+	pe, ok := err.(*os.PathError)
+	return ok && pe.Err == ErrOutsideWorkspace
+}